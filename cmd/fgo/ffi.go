@@ -9,10 +9,17 @@ import (
 
 	ffigen "github.com/czg99/flutter_gopher/ffi_gen"
 	"github.com/czg99/flutter_gopher/locales"
+	"github.com/czg99/flutter_gopher/models"
 	"github.com/nicksnyder/go-i18n/v2/i18n"
 	"github.com/spf13/cobra"
 )
 
+var (
+	watchMode   bool // 是否在生成后继续监听ffi目录的变化
+	checkMode   bool // 是否只检查已有文件与生成的代码是否一致
+	isolatePool bool // 是否生成在后台Dart isolate池中运行异步调用的代码
+)
+
 // ffiCmd 桥接代码生成命令
 var ffiCmd = &cobra.Command{
 	Use: "ffi",
@@ -26,6 +33,9 @@ var ffiCmd = &cobra.Command{
 
 使用示例:
 fgo ffi
+fgo ffi --watch
+fgo ffi --check
+fgo ffi --isolate-pool
 `,
 	}),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -65,13 +75,31 @@ func validateAndProcess() error {
 	}
 
 	goffiDir := "gosrc/ffi"
-	if err := ffigen.GenerateFfiCode(goffiDir, "lib/src/ffi"); err != nil {
-		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+	pkg, err := generateFfi(goffiDir)
+	if !watchMode {
+		return err
+	}
+
+	// 监听模式下生成失败不退出，等待源文件修改后重试
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+	}
+	return watchFfi(goffiDir, ffigen.NewBindings(pkg))
+}
+
+// generateFfi 生成ffi目录的桥接代码，返回解析得到的包
+func generateFfi(goffiDir string) (*models.Package, error) {
+	pkg, err := ffigen.GenerateFfiCode(goffiDir, "lib/src/ffi", ffigen.GenerateOptions{
+		Check:       checkMode,
+		IsolatePool: isolatePool,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
 			ID:    "fgo.ffi.gen.error",
 			Other: "生成FFI代码失败: %w",
 		}), err)
 	}
-	return nil
+	return pkg, nil
 }
 
 // findProjectRoot 查找pubspec.yaml的工程目录，并且目录中存在gosrc/ffi目录
@@ -143,4 +171,18 @@ func findProjectRoot() (root string, err error) {
 
 func init() {
 	rootCmd.AddCommand(ffiCmd)
+
+	ffiCmd.Flags().BoolVar(&watchMode, "watch", false, locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "fgo.ffi.watch.flag",
+		Other: "监听gosrc/ffi目录的变化并自动重新生成代码",
+	}))
+	ffiCmd.Flags().BoolVar(&checkMode, "check", false, locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "fgo.ffi.check.flag",
+		Other: "检查已有的生成文件是否与源文件一致，输出差异且不写入文件，不一致时返回非零退出码",
+	}))
+	ffiCmd.Flags().BoolVar(&isolatePool, "isolate-pool", false, locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "fgo.ffi.isolatepool.flag",
+		Other: "Dart的异步调用在后台isolate池中调用同步FFI函数，不再使用goroutine和端口返回结果",
+	}))
+	ffiCmd.MarkFlagsMutuallyExclusive("watch", "check")
 }
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ffigen "github.com/czg99/flutter_gopher/ffi_gen"
+	"github.com/czg99/flutter_gopher/locales"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// 监听模式检查文件变化的间隔，以及文件停止变化多久后重新生成
+const (
+	watchInterval = 500 * time.Millisecond
+	watchDebounce = 300 * time.Millisecond
+)
+
+// fileState 文件的修改时间和大小，用于检测文件变化
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchFfi 监听ffi目录中源文件的变化并重新生成代码，每次生成后输出声明的变化
+func watchFfi(goffiDir string, bindings ffigen.Bindings) error {
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "fgo.ffi.watch.start",
+		Other: "正在监听文件变化, 按 Ctrl+C 退出:",
+	}), goffiDir)
+
+	snapshot, err := snapshotDir(goffiDir)
+	if err != nil {
+		return err
+	}
+
+	for {
+		time.Sleep(watchInterval)
+		current, err := snapshotDir(goffiDir)
+		if err != nil {
+			return err
+		}
+		if maps.Equal(snapshot, current) {
+			continue
+		}
+
+		// 等待文件在防抖时间内不再变化，避免保存多个文件时重复生成
+		for {
+			time.Sleep(watchDebounce)
+			next, err := snapshotDir(goffiDir)
+			if err != nil {
+				return err
+			}
+			if maps.Equal(current, next) {
+				break
+			}
+			current = next
+		}
+		snapshot = current
+
+		log.Println(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "fgo.ffi.watch.changed",
+			Other: "检测到文件变化, 重新生成FFI代码...",
+		}))
+		pkg, err := generateFfi(goffiDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\n%v\n", err)
+			continue
+		}
+
+		newer := ffigen.NewBindings(pkg)
+		printBindingsDiff(bindings, newer)
+		bindings = newer
+	}
+}
+
+// snapshotDir 记录目录中除生成文件外所有Go源文件的状态
+func snapshotDir(dir string) (map[string]fileState, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "fgo.ffi.watch.readdir.error",
+			Other: "读取监听目录失败: %w",
+		}), err)
+	}
+
+	snapshot := make(map[string]fileState, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || name == "ffi.export.go" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// 文件可能在读取目录后被删除
+			continue
+		}
+		snapshot[filepath.Join(dir, name)] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return snapshot, nil
+}
+
+// printBindingsDiff 输出两次生成之间新增、删除和变化的声明
+func printBindingsDiff(old, newer ffigen.Bindings) {
+	added, removed, changed := old.Diff(newer)
+	if len(added)+len(removed)+len(changed) == 0 {
+		log.Println(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "fgo.ffi.watch.nochange",
+			Other: "桥接声明没有变化",
+		}))
+		return
+	}
+
+	log.Printf(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "fgo.ffi.watch.diff",
+		Other: "桥接声明变化: 新增 %d, 删除 %d, 修改 %d",
+	}), len(added), len(removed), len(changed))
+	for _, key := range added {
+		fmt.Println("  +", key)
+	}
+	for _, key := range removed {
+		fmt.Println("  -", key)
+	}
+	for _, key := range changed {
+		fmt.Println("  ~", key)
+	}
+}
@@ -0,0 +1,90 @@
+package ffigen
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/czg99/flutter_gopher/models"
+)
+
+// Bindings 生成的桥接声明摘要，键为声明的类别和名称，值为声明的签名
+// 用于比较两次生成之间新增、删除和变化的声明
+type Bindings map[string]string
+
+// NewBindings 从解析结果中提取桥接声明摘要
+func NewBindings(pkg *models.Package) Bindings {
+	bindings := make(Bindings)
+	if pkg == nil {
+		return bindings
+	}
+
+	for _, structType := range pkg.Structs {
+		bindings["struct "+structType.GoType()] = fieldsSignature(structType.Fields)
+	}
+	for _, enumType := range pkg.Enums {
+		values := make([]string, 0, len(enumType.Values))
+		for _, value := range enumType.Values {
+			values = append(values, value.Name+"="+value.Value)
+		}
+		bindings["enum "+enumType.GoType()] = enumType.Base.GoType() + "{" + strings.Join(values, ", ") + "}"
+	}
+	for _, namedType := range pkg.Named {
+		bindings["type "+namedType.GoType()] = namedType.Underlying.GoType()
+	}
+	for _, handleType := range pkg.Handles {
+		bindings["handle "+handleType.String()] = handleType.GoType()
+	}
+	for _, errorType := range pkg.Errors {
+		bindings["error "+errorType.GoType()] = fieldsSignature(errorType.Fields)
+	}
+	if pkg.Concurrency > 0 {
+		bindings["concurrency"] = strconv.Itoa(pkg.Concurrency)
+	}
+	for _, funcType := range pkg.Funcs {
+		name := funcType.Name
+		if funcType.Method != "" {
+			name = funcType.Recv.String() + "." + funcType.Method
+		}
+		signature := "(" + fieldsSignature(funcType.Params.Fields) + ") (" + fieldsSignature(funcType.Results.Fields) + ")"
+		if funcType.Stream != nil {
+			signature += " " + funcType.Stream.GoType()
+		}
+		if funcType.ZeroCopy {
+			signature += " zerocopy"
+		}
+		if funcType.Concurrency > 0 {
+			signature += " concurrency=" + strconv.Itoa(funcType.Concurrency)
+		}
+		bindings["func "+name] = signature
+	}
+	return bindings
+}
+
+// fieldsSignature 返回字段列表的签名
+func fieldsSignature(fields []*models.GoField) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field.Name+" "+field.GoType())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Diff 比较两次生成的声明，返回按名称排序的新增、删除和变化的声明
+func (b Bindings) Diff(newer Bindings) (added, removed, changed []string) {
+	for _, key := range slices.Sorted(maps.Keys(newer)) {
+		old, ok := b[key]
+		if !ok {
+			added = append(added, key)
+		} else if old != newer[key] {
+			changed = append(changed, key)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(b)) {
+		if _, ok := newer[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	return
+}
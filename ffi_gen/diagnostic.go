@@ -0,0 +1,261 @@
+package ffigen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"log"
+	"regexp"
+	"slices"
+
+	"github.com/czg99/flutter_gopher/locales"
+	"github.com/czg99/flutter_gopher/models"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// ParseError 解析错误，包含出错的源码位置和所在的函数或类型声明
+type ParseError struct {
+	Pos  token.Position
+	Decl string // 所在的声明名称
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return formatPosition(e.Pos, e.Decl, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Diagnostic 诊断模式下被跳过的声明及其原因
+type Diagnostic struct {
+	Pos  token.Position // 出错的源码位置
+	Decl string         // 被跳过的声明名称
+	Err  error
+}
+
+func (d Diagnostic) String() string {
+	return formatPosition(d.Pos, d.Decl, d.Err)
+}
+
+// formatPosition 按 file:line:col: 声明: 错误 的格式输出
+func formatPosition(pos token.Position, decl string, err error) string {
+	if decl == "" {
+		return fmt.Sprintf("%s: %v", pos, err)
+	}
+	return fmt.Sprintf("%s: %s: %v", pos, decl, err)
+}
+
+// enterDecl 设置当前正在解析的声明，返回恢复之前声明的函数
+func (p *GoSrcParser) enterDecl(name string) func() {
+	decl := p.curDecl
+	p.curDecl = name
+	return func() {
+		p.curDecl = decl
+	}
+}
+
+// wrapError 为错误附加节点的源码位置和当前声明，已包含位置的错误保持不变
+func (p *GoSrcParser) wrapError(node ast.Node, err error) error {
+	return p.declError(node, p.curDecl, err)
+}
+
+// declError 为错误附加节点的源码位置和指定的声明，已包含位置的错误保持不变
+func (p *GoSrcParser) declError(node ast.Node, decl string, err error) error {
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		return err
+	}
+	return &ParseError{Pos: p.curPkg.Fset.Position(node.Pos()), Decl: decl, Err: err}
+}
+
+// Diagnostics 返回诊断模式下收集到的问题
+func (p *GoSrcParser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// report 处理声明解析失败的错误
+// 诊断模式下记录问题并跳过该声明，否则直接返回错误
+func (p *GoSrcParser) report(decl string, err error) error {
+	if !p.Diagnose {
+		return err
+	}
+
+	diagnostic := Diagnostic{Decl: decl, Err: err}
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) {
+		diagnostic.Pos = parseErr.Pos
+		diagnostic.Err = parseErr.Err
+	}
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.diagnostic.skip",
+		Other: " - 警告: 跳过无法解析的声明:",
+	}), diagnostic)
+	p.diagnostics = append(p.diagnostics, diagnostic)
+	return nil
+}
+
+// pruneUnresolved 移除引用了不可用结构体或命名类型的声明
+// 被跳过或忽略的类型会导致引用它的声明无法生成，移除后继续检查直到没有新的声明被移除
+func (p *GoSrcParser) pruneUnresolved() error {
+	for {
+		known := make(map[string]bool, len(p.structs)+len(p.named))
+		for _, structType := range p.structs {
+			known[structType.GoType()] = true
+		}
+		for _, namedType := range p.named {
+			known[namedType.GoType()] = true
+		}
+
+		pruned := false
+		check := func(decl any, name string, types ...models.GoType) (bool, error) {
+			for _, t := range types {
+				if missing := unresolvedType(t, known); missing != "" {
+					pruned = true
+					return false, p.report(name, &ParseError{
+						Pos:  p.declPos[decl],
+						Decl: name,
+						Err: fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+							ID:    "ffigen.srcparser.diagnostic.unresolved",
+							Other: "引用的类型 %s 不可用",
+						}), missing),
+					})
+				}
+			}
+			return true, nil
+		}
+
+		var err error
+		p.structs = slices.DeleteFunc(p.structs, func(structType *models.GoStructType) bool {
+			keep, e := check(structType, structType.String(), fieldTypes(structType.Fields)...)
+			err = errors.Join(err, e)
+			return !keep
+		})
+		p.named = slices.DeleteFunc(p.named, func(namedType *models.GoNamedType) bool {
+			keep, e := check(namedType, namedType.String(), namedType.Underlying)
+			err = errors.Join(err, e)
+			return !keep
+		})
+		p.funcs = slices.DeleteFunc(p.funcs, func(funcType *models.GoFuncType) bool {
+			types := append(fieldTypes(funcType.Params.Fields), fieldTypes(funcType.Results.Fields)...)
+			if funcType.Stream != nil {
+				types = append(types, funcType.Stream)
+			}
+			keep, e := check(funcType, funcType.Name, types...)
+			err = errors.Join(err, e)
+			if !keep {
+				removeMethod(funcType)
+			}
+			return !keep
+		})
+		if err != nil {
+			return err
+		}
+		if !pruned {
+			break
+		}
+	}
+
+	if len(p.diagnostics) > 0 {
+		p.pruneImports()
+	}
+	return nil
+}
+
+// qualifiedNameRegexp 匹配Go类型字符串中引用的包名
+var qualifiedNameRegexp = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.[A-Za-z_]`)
+
+// pruneImports 移除只被跳过的声明引用的导入包，避免生成的Go代码中存在未使用的导入
+func (p *GoSrcParser) pruneImports() {
+	used := make(map[string]bool)
+	addType := func(t models.GoType) {
+		for _, match := range qualifiedNameRegexp.FindAllStringSubmatch(t.GoType(), -1) {
+			used[match[1]] = true
+		}
+	}
+
+	for _, structType := range p.structs {
+		addType(structType)
+		for _, t := range fieldTypes(structType.Fields) {
+			addType(t)
+		}
+	}
+	for _, namedType := range p.named {
+		addType(namedType)
+		addType(namedType.Underlying)
+	}
+	for _, enumType := range p.enums {
+		addType(enumType)
+	}
+	for _, handleType := range p.handles {
+		addType(handleType)
+	}
+	for _, funcType := range p.funcs {
+		for _, t := range fieldTypes(funcType.Params.Fields) {
+			addType(t)
+		}
+		for _, t := range fieldTypes(funcType.Results.Fields) {
+			addType(t)
+		}
+		if funcType.Stream != nil {
+			addType(funcType.Stream)
+		}
+	}
+
+	for path, name := range p.imports {
+		if !used[name] {
+			delete(p.imports, path)
+		}
+	}
+}
+
+// removeMethod 从接收者的方法列表中移除方法
+func removeMethod(funcType *models.GoFuncType) {
+	switch recv := funcType.Recv.(type) {
+	case *models.GoStructType:
+		recv.Methods = slices.DeleteFunc(recv.Methods, func(m *models.GoFuncType) bool { return m == funcType })
+	case *models.GoHandleType:
+		recv.Methods = slices.DeleteFunc(recv.Methods, func(m *models.GoFuncType) bool { return m == funcType })
+	}
+}
+
+// fieldTypes 返回字段列表中的所有类型
+func fieldTypes(fields []*models.GoField) []models.GoType {
+	types := make([]models.GoType, 0, len(fields))
+	for _, field := range fields {
+		types = append(types, field.Type)
+	}
+	return types
+}
+
+// unresolvedType 返回类型中引用的不可用结构体或命名类型，全部可用时返回空字符串
+func unresolvedType(t models.GoType, known map[string]bool) string {
+	switch t := t.(type) {
+	case *models.GoIdentType, *models.GoNamedType:
+		if !known[t.GoType()] {
+			return t.GoType()
+		}
+	case *models.GoSliceType:
+		return unresolvedType(t.Inner, known)
+	case *models.GoPointerType:
+		return unresolvedType(t.Inner, known)
+	case *models.GoArrayType:
+		return unresolvedType(t.Inner, known)
+	case *models.GoChanType:
+		return unresolvedType(t.Inner, known)
+	case *models.GoMapType:
+		if missing := unresolvedType(t.Key, known); missing != "" {
+			return missing
+		}
+		return unresolvedType(t.Value, known)
+	case *models.GoCallbackType:
+		for _, field := range t.Args.Fields {
+			if missing := unresolvedType(field.Type, known); missing != "" {
+				return missing
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package ffigen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	diffContext  = 3    // 差异前后保留的上下文行数
+	maxDiffEdits = 1000 // 逐行比较的最大编辑数，超过时整段替换
+)
+
+// diffLine 差异中的一行，kind 为 ' '、'-' 或 '+'
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff 返回两个文件内容的统一格式差异
+func unifiedDiff(name string, oldContent, newContent []byte) string {
+	lines := diffLines(splitLines(oldContent), splitLines(newContent))
+
+	// 每行之前旧文件和新文件已经出现的行数，用于计算差异块的起始行号
+	oldNo := make([]int, len(lines)+1)
+	newNo := make([]int, len(lines)+1)
+	for i, line := range lines {
+		oldNo[i+1], newNo[i+1] = oldNo[i], newNo[i]
+		if line.kind != '+' {
+			oldNo[i+1]++
+		}
+		if line.kind != '-' {
+			newNo[i+1]++
+		}
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "--- a/%s\n+++ b/%s\n", name, name)
+	for start := 0; start < len(lines); {
+		for start < len(lines) && lines[start].kind == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// 间隔不超过两倍上下文的变化合并到同一个差异块
+		end := start
+		for i := start; i < len(lines); i++ {
+			if lines[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		from := max(start-diffContext, 0)
+		to := min(end+diffContext, len(lines))
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(oldNo[from], oldNo[to]-oldNo[from]),
+			hunkRange(newNo[from], newNo[to]-newNo[from]))
+		for _, line := range lines[from:to] {
+			buf.WriteByte(line.kind)
+			buf.WriteString(line.text)
+			buf.WriteByte('\n')
+		}
+		start = to
+	}
+	return buf.String()
+}
+
+// hunkRange 返回差异块头部的行号范围，空范围的行号为前一行
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+// splitLines 按行拆分文件内容
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// diffLines 逐行比较两个文件，先去掉相同的开头和结尾，再逐行比较中间部分
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		lines = append(lines, diffLine{' ', text})
+	}
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', text})
+	}
+	return lines
+}
+
+// diffMiddle 用Myers算法比较两段内容，差异过多时整段删除再添加
+func diffMiddle(a, b []string) []diffLine {
+	n, m := len(a), len(b)
+	offset := n + m
+	v := make([]int, 2*offset+2)
+
+	// trace[d] 保存第d步后每条对角线k能到达的最远x，下标为 k+d
+	var trace [][]int
+	found := false
+	for d := 0; d <= min(n+m, maxDiffEdits) && !found; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+			}
+		}
+		trace = append(trace, slices.Clone(v[offset-len(trace):offset+len(trace)+1]))
+	}
+
+	if !found {
+		lines := make([]diffLine, 0, n+m)
+		for _, text := range a {
+			lines = append(lines, diffLine{'-', text})
+		}
+		for _, text := range b {
+			lines = append(lines, diffLine{'+', text})
+		}
+		return lines
+	}
+
+	// 从终点回溯每一步的编辑
+	lines := make([]diffLine, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			prevK = k + 1
+		}
+		prevX := prev[prevK+d-1]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			lines = append(lines, diffLine{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			lines = append(lines, diffLine{'+', b[y-1]})
+			y--
+		} else {
+			lines = append(lines, diffLine{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		lines = append(lines, diffLine{' ', a[x-1]})
+		x--
+		y--
+	}
+	slices.Reverse(lines)
+	return lines
+}
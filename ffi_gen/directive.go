@@ -0,0 +1,35 @@
+package ffigen
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// directivePrefix fgo指令的注释前缀，指令写在声明的文档注释中，如 //fgo:handle
+const directivePrefix = "//fgo:"
+
+// parseDirective 在注释中查找指定名称的fgo指令，返回指令的参数
+func parseDirective(doc *ast.CommentGroup, name string) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+
+	for _, comment := range doc.List {
+		text, ok := strings.CutPrefix(comment.Text, directivePrefix)
+		if !ok {
+			continue
+		}
+
+		directive, args, _ := strings.Cut(text, " ")
+		if directive == name {
+			return strings.TrimSpace(args), true
+		}
+	}
+	return "", false
+}
+
+// hasDirective 判断注释中是否存在指定名称的fgo指令
+func hasDirective(doc *ast.CommentGroup, name string) bool {
+	_, ok := parseDirective(doc, name)
+	return ok
+}
@@ -6,9 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strings"
 	"text/template"
 
 	"github.com/czg99/flutter_gopher/locales"
@@ -19,12 +22,18 @@ import (
 //go:embed templates/*
 var templateFiles embed.FS
 
+// GenerateOptions 桥接代码生成选项
+type GenerateOptions struct {
+	Check       bool // 只比较生成的代码与已有文件并输出差异，不写入文件
+	IsolatePool bool // Dart的异步调用在后台isolate池中调用同步FFI函数
+}
+
 // GenerateFfiCode 为给定的源路径生成桥接代码，并将生成的代码写入指定的输出目录
-// 如果代码生成失败则返回错误
-func GenerateFfiCode(goffiDir, dartOutDir string) error {
+// 返回解析得到的包，如果代码生成失败或检查模式下存在差异则返回错误
+func GenerateFfiCode(goffiDir, dartOutDir string, opts GenerateOptions) (*models.Package, error) {
 	// 验证输出路径
 	if dartOutDir == "" {
-		return errors.New(locales.MustLocalizeMessage(&i18n.Message{
+		return nil, errors.New(locales.MustLocalizeMessage(&i18n.Message{
 			ID:    "ffigen.target.nodartpath.error",
 			Other: "未指定Dart输出路径",
 		}))
@@ -35,10 +44,13 @@ func GenerateFfiCode(goffiDir, dartOutDir string) error {
 		ID:    "ffigen.target.parse.info",
 		Other: "解析gosrc的ffi目录文件...",
 	}))
+	// 无法解析的声明只跳过并给出警告，不影响其他声明的生成
 	parser := NewGoSrcParser()
+	parser.Diagnose = true
+	parser.ReadOnly = opts.Check
 	pkg, err := parser.Parse(goffiDir, []string{"ffi.export.go"})
 	if err != nil {
-		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
 			ID:    "ffigen.target.parse.error",
 			Other: "解析gosrc的ffi目录文件失败: %w",
 		}), err)
@@ -47,6 +59,15 @@ func GenerateFfiCode(goffiDir, dartOutDir string) error {
 	goOut := filepath.Join(goffiDir, "ffi.export.go")
 	dartOut := filepath.Join(dartOutDir, "ffi.dart")
 
+	if opts.Check {
+		// 先输出跳过的声明，避免检查通过时忽略了无法生成的声明
+		printDiagnostics(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.target.check.diagnostics.warn",
+			Other: "跳过了 %d 个无法解析的声明, 检查结果不包含这些声明:",
+		}), parser.Diagnostics())
+		return pkg, checkGenerated(pkg, goOut, dartOut, opts)
+	}
+
 	// 如果指定了输出路径则生成Go代码
 	if goOut != "" {
 		log.Println(locales.MustLocalizeMessage(&i18n.Message{
@@ -54,7 +75,7 @@ func GenerateFfiCode(goffiDir, dartOutDir string) error {
 			Other: "生成CGO代码...",
 		}))
 		if err = NewGoGenerator(*pkg).Generate(goOut); err != nil {
-			return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
 				ID:    "ffigen.target.gen.go.error",
 				Other: "生成CGO代码失败: %w",
 			}), err)
@@ -67,14 +88,71 @@ func GenerateFfiCode(goffiDir, dartOutDir string) error {
 			ID:    "ffigen.target.gen.dart.info",
 			Other: "生成Dart代码...",
 		}))
-		if err = NewDartGenerator(*pkg).Generate(dartOut); err != nil {
-			return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		if err = NewDartGenerator(*pkg, opts).Generate(dartOut); err != nil {
+			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
 				ID:    "ffigen.target.gen.dart.error",
 				Other: "生成Dart代码失败: %w",
 			}), err)
 		}
 	}
 
+	printDiagnostics(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.target.diagnostics.warn",
+		Other: "生成完成, 跳过了 %d 个无法解析的声明:",
+	}), parser.Diagnostics())
+
+	return pkg, nil
+}
+
+// printDiagnostics 输出跳过的无法解析的声明，format 为包含声明数量的标题
+func printDiagnostics(format string, diagnostics []Diagnostic) {
+	if len(diagnostics) == 0 {
+		return
+	}
+	log.Printf(format, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		log.Println("  -", diagnostic)
+	}
+}
+
+// checkGenerated 比较生成的代码与已有文件，输出差异，存在差异时返回错误
+func checkGenerated(pkg *models.Package, goOut, dartOut string, opts GenerateOptions) error {
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.target.check.info",
+		Other: "检查生成的代码是否与已有文件一致...",
+	}))
+
+	targets := []struct {
+		generator *FfiGenerator
+		dest      string
+	}{
+		{NewGoGenerator(*pkg), goOut},
+		{NewDartGenerator(*pkg, opts), dartOut},
+	}
+
+	var drifted []string
+	for _, target := range targets {
+		diff, err := target.generator.Check(target.dest)
+		if err != nil {
+			return err
+		}
+		if diff != "" {
+			fmt.Print(diff)
+			drifted = append(drifted, target.dest)
+		}
+	}
+
+	if len(drifted) > 0 {
+		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.target.check.drift",
+			Other: "生成的代码与已有文件不一致，请重新运行 fgo ffi: %s",
+		}), strings.Join(drifted, ", "))
+	}
+
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.target.check.ok",
+		Other: "生成的代码与已有文件一致",
+	}))
 	return nil
 }
 
@@ -82,13 +160,35 @@ func GenerateFfiCode(goffiDir, dartOutDir string) error {
 // 处理Go结构体和函数以创建FFI兼容的代码
 type FfiGenerator struct {
 	models.Package
-	Slices []*models.GoSliceType   // 需要桥接的切片类型
-	Ptrs   []*models.GoPointerType // 需要桥接的指针类型
+	Slices    []*models.GoSliceType    // 需要桥接的切片类型
+	Ptrs      []*models.GoPointerType  // 需要桥接的指针类型
+	Maps      []*models.GoMapType      // 需要桥接的Map类型
+	Arrays    []*models.GoArrayType    // 需要桥接的固定大小数组类型
+	Callbacks []*models.GoCallbackType // 函数参数中的回调类型
+
+	Packed        map[string]*models.PackedLayout // 可以紧凑布局传递的结构体切片，键为切片的MapName
+	HandleHolders map[string]bool                 // 包含句柄的类型，键为类型的MapName
+
+	IsolatePool bool            // 是否生成后台isolate池，异步调用在isolate池中调用同步FFI函数
+	Isolated    map[string]bool // 在isolate池中运行异步调用的函数，键为函数的CType
 
 	generatedCode []byte // 最终生成的代码
 	templatePath  string // 模板文件路径
 }
 
+// HasZeroCopy 判断是否存在标记了 //fgo:zerocopy 的函数，存在时生成零复制传递 []byte 的辅助函数
+func (g *FfiGenerator) HasZeroCopy() bool {
+	return slices.ContainsFunc(g.Funcs, func(funcType *models.GoFuncType) bool {
+		return funcType.ZeroCopy
+	})
+}
+
+// HasExternalMemory 判断是否存在交给Dart持有的C内存，存在时导出 fg_free 供Dart的 NativeFinalizer 释放内存
+// 零复制返回的 []byte 和紧凑布局的结构体切片在Dart中直接引用Go分配的内存
+func (g *FfiGenerator) HasExternalMemory() bool {
+	return g.HasZeroCopy() || len(g.Packed) > 0
+}
+
 // NewGoGenerator 创建一个新的Go桥接代码生成器
 // 使用Go桥接模板初始化生成器
 func NewGoGenerator(pkg models.Package) *FfiGenerator {
@@ -100,9 +200,10 @@ func NewGoGenerator(pkg models.Package) *FfiGenerator {
 
 // NewDartGenerator 创建一个新的Dart桥接代码生成器
 // 使用Dart桥接模板初始化生成器
-func NewDartGenerator(pkg models.Package) *FfiGenerator {
+func NewDartGenerator(pkg models.Package, opts GenerateOptions) *FfiGenerator {
 	return &FfiGenerator{
 		Package:      pkg,
+		IsolatePool:  opts.IsolatePool,
 		templatePath: "templates/ffi.dart.tmpl",
 	}
 }
@@ -113,6 +214,44 @@ func NewDartGenerator(pkg models.Package) *FfiGenerator {
 //
 // 返回生成过程中出现的错误
 func (g *FfiGenerator) Generate(dest string) error {
+	if err := g.render(); err != nil {
+		return err
+	}
+
+	// 将生成的代码写入文件
+	if err := g.writeToFile(dest); err != nil {
+		return err
+	}
+
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.template.write.success",
+		Other: "生成代码成功:",
+	}), dest)
+	return nil
+}
+
+// Check 生成代码并与目标文件比较，不写入文件
+// 返回统一格式的差异，内容一致时返回空字符串，目标文件不存在时视为空文件
+func (g *FfiGenerator) Check(dest string) (string, error) {
+	if err := g.render(); err != nil {
+		return "", err
+	}
+
+	existing, err := os.ReadFile(dest)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.check.read.error",
+			Other: "读取已有文件失败: %w",
+		}), err)
+	}
+	if bytes.Equal(existing, g.generatedCode) {
+		return "", nil
+	}
+	return unifiedDiff(filepath.ToSlash(dest), existing, g.generatedCode), nil
+}
+
+// render 处理包数据并执行模板，生成的代码保存在 generatedCode 中
+func (g *FfiGenerator) render() error {
 	// 处理包数据为代码生成做准备
 	g.processSpecialTypes()
 
@@ -133,16 +272,6 @@ func (g *FfiGenerator) Generate(dest string) error {
 
 	// 清理生成的代码，移除多余的空行
 	g.generatedCode = removeExcessiveEmptyLines(buffer.Bytes())
-
-	// 将生成的代码写入文件
-	if err = g.writeToFile(dest); err != nil {
-		return err
-	}
-
-	log.Println(locales.MustLocalizeMessage(&i18n.Message{
-		ID:    "ffigen.template.write.success",
-		Other: "生成代码成功:",
-	}), dest)
 	return nil
 }
 
@@ -192,29 +321,77 @@ func (g *FfiGenerator) writeToFile(dest string) error {
 	return nil
 }
 
-// processSpecialTypes 处理切片、指针和通道类型
+// processSpecialTypes 处理切片、指针、Map、数组、通道和回调类型
 func (g *FfiGenerator) processSpecialTypes() {
 	// 从结构体和函数中收集所有特殊类型
-	sliceMap, ptrMap := g.collectSpecialTypes()
+	sliceMap, ptrMap, mapMap, arrayMap := g.collectSpecialTypes()
 
 	// 将map转换为slice以便模板处理
 	g.Slices = mapToSlice(sliceMap)
 	g.Ptrs = mapToSlice(ptrMap)
+	g.Maps = mapToSlice(mapMap)
+	g.Arrays = mapToSlice(arrayMap)
+	g.Callbacks = g.collectCallbacks()
+	g.Packed = g.collectPackedLayouts()
+	g.HandleHolders = g.collectHandleHolders()
+	if g.IsolatePool {
+		g.Isolated = g.collectIsolated()
+	}
+
+	// C结构体按值包含的结构体必须先定义
+	g.Structs = sortStructsByDependency(g.Structs)
 }
 
-// mapToSlice 将字段的map转换为slice
+// sortStructsByDependency 按值依赖关系对结构体排序
+// 结构体字段或固定大小数组中直接包含的结构体会排在前面
+func sortStructsByDependency(structs []*models.GoStructType) []*models.GoStructType {
+	structMap := make(map[string]*models.GoStructType, len(structs))
+	for _, structType := range structs {
+		structMap[structType.GoType()] = structType
+	}
+
+	result := make([]*models.GoStructType, 0, len(structs))
+	visited := make(map[string]bool, len(structs))
+	var visit func(structType *models.GoStructType)
+	visit = func(structType *models.GoStructType) {
+		if visited[structType.GoType()] {
+			return
+		}
+		visited[structType.GoType()] = true
+
+		for _, field := range structType.Fields {
+			fieldType := field.Type
+			if arrayType, ok := fieldType.(*models.GoArrayType); ok {
+				fieldType = arrayType.Inner
+			}
+			if dep, ok := structMap[fieldType.GoType()]; ok {
+				visit(dep)
+			}
+		}
+		result = append(result, structType)
+	}
+
+	for _, structType := range structs {
+		visit(structType)
+	}
+	return result
+}
+
+// mapToSlice 将字段的map转换为slice，按名称排序以保证每次生成的代码一致
 func mapToSlice[T models.GoType](fieldMap map[string]T) []T {
 	result := make([]T, 0, len(fieldMap))
-	for _, v := range fieldMap {
-		result = append(result, v)
+	for _, key := range slices.Sorted(maps.Keys(fieldMap)) {
+		result = append(result, fieldMap[key])
 	}
 	return result
 }
 
-// collectSpecialTypes 查找结构体和函数中的所有切片、指针和通道类型
-func (g *FfiGenerator) collectSpecialTypes() (sliceMap map[string]*models.GoSliceType, ptrMap map[string]*models.GoPointerType) {
+// collectSpecialTypes 查找结构体和函数中的所有切片、指针、Map、数组、通道和回调类型
+func (g *FfiGenerator) collectSpecialTypes() (sliceMap map[string]*models.GoSliceType, ptrMap map[string]*models.GoPointerType, mapMap map[string]*models.GoMapType, arrayMap map[string]*models.GoArrayType) {
 	sliceMap = make(map[string]*models.GoSliceType)
 	ptrMap = make(map[string]*models.GoPointerType)
+	mapMap = make(map[string]*models.GoMapType)
+	arrayMap = make(map[string]*models.GoArrayType)
 
 	// 处理字段并查找特殊类型的辅助函数
 	var processTypes func(t models.GoType)
@@ -226,6 +403,22 @@ func (g *FfiGenerator) collectSpecialTypes() (sliceMap map[string]*models.GoSlic
 		case *models.GoPointerType:
 			ptrMap[t.MapName()] = t
 			processTypes(t.Inner)
+		case *models.GoMapType:
+			// Map通过键和值两个切片传递
+			mapMap[t.MapName()] = t
+			processTypes(t.Keys())
+			processTypes(t.Values())
+		case *models.GoArrayType:
+			arrayMap[t.MapName()] = t
+			processTypes(t.Inner)
+		case *models.GoChanType:
+			processTypes(t.Inner)
+		case *models.GoNamedType:
+			processTypes(t.Underlying)
+		case *models.GoCallbackType:
+			for _, field := range t.Args.Fields {
+				processTypes(field.Type)
+			}
 		}
 	}
 
@@ -236,6 +429,11 @@ func (g *FfiGenerator) collectSpecialTypes() (sliceMap map[string]*models.GoSlic
 		}
 	}
 
+	// 处理所有命名类型的底层类型
+	for _, namedType := range g.Named {
+		processTypes(namedType.Underlying)
+	}
+
 	// 处理所有函数参数和结果
 	for _, funcType := range g.Funcs {
 		for _, field := range funcType.Params.Fields {
@@ -244,10 +442,187 @@ func (g *FfiGenerator) collectSpecialTypes() (sliceMap map[string]*models.GoSlic
 		for _, field := range funcType.Results.Fields {
 			processTypes(field.Type)
 		}
+		if funcType.Stream != nil {
+			processTypes(funcType.Stream)
+		}
 	}
 	return
 }
 
+// collectPackedLayouts 查找元素为只包含固定大小数值字段的结构体的切片，这些切片以紧凑布局整块传递
+func (g *FfiGenerator) collectPackedLayouts() map[string]*models.PackedLayout {
+	structMap := make(map[string]*models.GoStructType, len(g.Structs))
+	for _, structType := range g.Structs {
+		structMap[structType.GoType()] = structType
+	}
+
+	packed := make(map[string]*models.PackedLayout)
+	for _, sliceType := range g.Slices {
+		structType, ok := structMap[sliceType.Inner.GoType()]
+		if !ok {
+			continue
+		}
+		if layout := models.NewPackedLayout(structType); layout != nil {
+			packed[sliceType.MapName()] = layout
+		}
+	}
+	return packed
+}
+
+// collectHandleHolders 查找直接或间接包含句柄的类型
+// 结果中的句柄在转换时新建，结果没有发送到Dart时需要遍历这些类型释放句柄
+func (g *FfiGenerator) collectHandleHolders() map[string]bool {
+	structMap := make(map[string]*models.GoStructType, len(g.Structs))
+	for _, structType := range g.Structs {
+		structMap[structType.GoType()] = structType
+	}
+
+	// 结构体可能递归引用自身，重复遍历直到结果不再变化
+	holders := make(map[string]bool)
+	visited := make(map[string]bool)
+	var holds func(t models.GoType) bool
+	holds = func(t models.GoType) bool {
+		if identType, ok := t.(*models.GoIdentType); ok {
+			structType, ok := structMap[identType.GoType()]
+			return ok && holds(structType)
+		}
+		if visited[t.MapName()] {
+			return holders[t.MapName()]
+		}
+		visited[t.MapName()] = true
+
+		result := false
+		switch t := t.(type) {
+		case *models.GoHandleType:
+			result = true
+		case *models.GoSliceType:
+			result = holds(t.Inner)
+		case *models.GoPointerType:
+			result = holds(t.Inner)
+		case *models.GoMapType:
+			keys, values := holds(t.Keys()), holds(t.Values())
+			result = keys || values
+		case *models.GoArrayType:
+			result = holds(t.Inner)
+		case *models.GoNamedType:
+			result = holds(t.Underlying)
+		case *models.GoStructType:
+			for _, field := range t.Fields {
+				// 每个字段都要遍历，字段类型的结果也会被使用
+				if holds(field.Type) {
+					result = true
+				}
+			}
+		}
+		if result {
+			holders[t.MapName()] = true
+		}
+		return result
+	}
+
+	for {
+		found := len(holders)
+		clear(visited)
+		for _, structType := range g.Structs {
+			holds(structType)
+		}
+		for _, funcType := range g.Funcs {
+			holds(funcType.Results)
+			if funcType.Stream != nil {
+				holds(funcType.Stream.Inner)
+			}
+		}
+		for _, callback := range g.Callbacks {
+			holds(callback.Args)
+		}
+		if len(holders) == found {
+			break
+		}
+	}
+	return holders
+}
+
+// collectIsolated 查找可以在isolate池中运行异步调用的函数
+// 参数和返回值在isolate之间复制，持有Go对象句柄、包含回调、可以取消或零复制的函数仍通过端口返回结果
+// 限制了并发数量的函数需要在Go端排队，也通过端口返回结果，包声明上指定了默认并发数量时所有函数都通过端口返回结果
+func (g *FfiGenerator) collectIsolated() map[string]bool {
+	isolated := make(map[string]bool)
+	if g.Concurrency > 0 {
+		return isolated
+	}
+
+	structMap := make(map[string]*models.GoStructType, len(g.Structs))
+	for _, structType := range g.Structs {
+		structMap[structType.GoType()] = structType
+	}
+
+	// sendable 判断类型的Dart对象复制到其他isolate后是否仍然有效
+	// 结构体的结果按名称缓存，递归引用自身时先视为有效
+	structSendable := make(map[string]bool)
+	var sendable func(t models.GoType) bool
+	sendable = func(t models.GoType) bool {
+		switch t := t.(type) {
+		case *models.GoHandleType, *models.GoCallbackType:
+			return false
+		case *models.GoSliceType:
+			return sendable(t.Inner)
+		case *models.GoPointerType:
+			return sendable(t.Inner)
+		case *models.GoMapType:
+			return sendable(t.Keys()) && sendable(t.Values())
+		case *models.GoArrayType:
+			return sendable(t.Inner)
+		case *models.GoNamedType:
+			return sendable(t.Underlying)
+		case *models.GoIdentType:
+			structType, ok := structMap[t.GoType()]
+			if !ok {
+				return true
+			}
+			if result, ok := structSendable[t.GoType()]; ok {
+				return result
+			}
+			structSendable[t.GoType()] = true
+			structSendable[t.GoType()] = sendable(structType)
+			return structSendable[t.GoType()]
+		case *models.GoStructType:
+			if t.HasPtrMethods() {
+				return false
+			}
+			for _, field := range t.Fields {
+				if !sendable(field.Type) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	for _, funcType := range g.Funcs {
+		if funcType.Stream != nil || funcType.PtrRecv || funcType.HasContext || funcType.ZeroCopy || funcType.Concurrency > 0 {
+			continue
+		}
+		if !sendable(funcType.Params) || !sendable(funcType.Results) {
+			continue
+		}
+		isolated[funcType.CType()] = true
+	}
+	return isolated
+}
+
+// collectCallbacks 按函数声明顺序收集所有回调参数
+func (g *FfiGenerator) collectCallbacks() []*models.GoCallbackType {
+	var callbacks []*models.GoCallbackType
+	for _, funcType := range g.Funcs {
+		for _, field := range funcType.Params.Fields {
+			if callbackType, ok := field.Type.(*models.GoCallbackType); ok {
+				callbacks = append(callbacks, callbackType)
+			}
+		}
+	}
+	return callbacks
+}
+
 // removeExcessiveEmptyLines 从生成的代码中移除多余的空行
 func removeExcessiveEmptyLines(code []byte) []byte {
 	emptyLinePattern := regexp.MustCompile(`(\r\n|\n){3,}`)
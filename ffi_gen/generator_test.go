@@ -0,0 +1,191 @@
+package ffigen
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testDartapi 替代Dart API的测试实现，端口为负数时发送失败，与Dart已关闭端口的情况一致
+const testDartapi = `package dartapi
+
+import "unsafe"
+
+func SendToDartPort(port int64, data unsafe.Pointer) bool {
+	return port >= 0
+}
+`
+
+// writeTestModule 在临时目录中创建包含 ffi 包的Go模块，返回模块根目录
+func writeTestModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	files["go.mod"] = "module fgtest\n\ngo 1.23\n"
+	files["dartapi/dartapi.go"] = testDartapi
+	files["mobileinit/mobileinit.go"] = "package mobileinit\n"
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// generateTestCode 解析模块中的 ffi 包，返回生成的Go和Dart代码
+func generateTestCode(t *testing.T, root string, opts GenerateOptions) (goCode, dartCode string) {
+	t.Helper()
+	parser := NewGoSrcParser()
+	parser.ReadOnly = true
+	pkg, err := parser.Parse(filepath.Join(root, "ffi"), []string{"ffi.export.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goGenerator := NewGoGenerator(*pkg)
+	if err := goGenerator.render(); err != nil {
+		t.Fatal(err)
+	}
+	dartGenerator := NewDartGenerator(*pkg, opts)
+	if err := dartGenerator.render(); err != nil {
+		t.Fatal(err)
+	}
+	return string(goGenerator.generatedCode), string(dartGenerator.generatedCode)
+}
+
+// runGeneratedTest 将生成的Go代码和测试写入 ffi 包并运行其中的测试
+// _test.go 文件中不能使用cgo，测试通过 zeroParams 获取参数类型的零值来调用导出函数
+func runGeneratedTest(t *testing.T, root, goCode, test string) {
+	t.Helper()
+	if out, err := exec.Command("go", "env", "CGO_ENABLED").Output(); err != nil || strings.TrimSpace(string(out)) != "1" {
+		t.Skip("cgo is not available")
+	}
+
+	files := map[string]string{
+		"ffi.export.go":      goCode,
+		"ffi.export_test.go": test,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, "ffi", name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command("go", "test", "./ffi")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=-mod=mod")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated code test failed: %v\n%s", err, out)
+	}
+}
+
+// TestPtrRecvHandleKeptAfterCancel 取消指针接收者方法的异步调用后，Dart持有的句柄仍然有效
+func TestPtrRecvHandleKeptAfterCancel(t *testing.T) {
+	root := writeTestModule(t, map[string]string{
+		"ffi/ffi.go": `package ffi
+
+import "context"
+
+type Session struct {
+	Name string
+	hits int
+}
+
+func (s *Session) Hit(ctx context.Context) int {
+	s.hits++
+	return s.hits
+}
+`,
+	})
+	goCode, _ := generateTestCode(t, root, GenerateOptions{})
+
+	test := `package ffi
+
+import (
+	"testing"
+	"time"
+)
+
+func zeroParams[P, R any](func(P) R) (params P) {
+	return
+}
+
+// 模拟Dart持有句柄后取消一次调用，再调用同一个接收者
+func TestHitAfterCancel(t *testing.T) {
+	params := zeroParams(fg_session__hit)
+	first := fg_session__hit(params)
+	params.fg_recv = first.fg_recv
+	params.fg_handle = first.fg_handle
+
+	const port = -1
+	fg_session__hit_async(port, params)
+	fg_session__hit_cancel(port)
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		metrics := fg_queue_metrics()
+		if metrics.running == 0 && metrics.queued == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("cancelled call did not finish")
+		}
+	}
+
+	if loadHandle(uintptr(params.fg_handle)) == nil {
+		t.Fatalf("handle %d held by Dart was released", params.fg_handle)
+	}
+	if third := fg_session__hit(params); third.res_0 != 3 {
+		t.Fatalf("receiver state lost: got %d hits, want 3", third.res_0)
+	}
+}
+`
+	runGeneratedTest(t, root, goCode, test)
+}
+
+// TestIsolatePoolKeepsConcurrencyLimit 限制了并发数量的函数在isolate池模式下仍通过Go端的队列运行
+func TestIsolatePoolKeepsConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		isolated map[string]bool
+	}{
+		{
+			name: "function limit",
+			src: `package ffi
+
+//fgo:concurrency 2
+func Limited(n int) int { return n }
+
+func Free(n int) int { return n }
+`,
+			isolated: map[string]bool{"fg_free": true},
+		},
+		{
+			name: "package limit",
+			src: `//fgo:concurrency 4
+package ffi
+
+func Free(n int) int { return n }
+`,
+			isolated: map[string]bool{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeTestModule(t, map[string]string{"ffi/ffi.go": tt.src})
+			_, dartCode := generateTestCode(t, root, GenerateOptions{IsolatePool: true})
+
+			for _, fn := range []string{"limited", "free"} {
+				pooled := strings.Contains(dartCode, fn+"Async(int n) => _fgIsolatePool.run(")
+				if want := tt.isolated["fg_"+fn]; pooled != want {
+					t.Errorf("%sAsync runs in isolate pool: got %v, want %v", fn, pooled, want)
+				}
+			}
+		})
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
+	"go/types"
 	"log"
 	"os"
 	"path/filepath"
 	"reflect"
 	"slices"
+	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/czg99/flutter_gopher/locales"
@@ -22,21 +26,51 @@ import (
 // GoSrcParser 实现了 Go 代码的 Parser 接口
 type GoSrcParser struct {
 	models.ProjectNaming
-
+	Diagnose bool // 诊断模式，跳过无法解析的声明并收集问题，而不是在第一个错误时失败
+	ReadOnly bool // 只读模式，只读取已有的 .timestamp 文件，不存在时不创建
+
+	module    string
+	pkgs      []*packages.Package
+	curPkg    *packages.Package // 当前正在解析的包
+	curDecl   string            // 当前正在解析的函数或类型声明，用于错误信息
+	nodePkgs  map[ast.Node]*packages.Package
 	typeNodes []*ast.TypeSpec
 	funcNodes []*ast.FuncDecl
 
-	structs []*models.GoStructType
-	funcs   []*models.GoFuncType
+	structs       []*models.GoStructType
+	enums         []*models.GoEnumType
+	named         []*models.GoNamedType
+	handles       []*models.GoHandleType
+	errors        []*models.GoErrorType
+	funcs         []*models.GoFuncType
+	imports       map[string]string
+	importedTypes map[string]bool
+	enumTypes     map[string]*models.GoEnumType
+	namedTypes    map[string]*models.GoNamedType
+	handleTypes   map[string]*models.GoHandleType
+	typeArgs      map[*types.TypeName]models.GoType // 展开泛型结构体时类型参数对应的类型实参
+	concurrency   int                               // 包声明上 //fgo:concurrency 指定的异步调用默认最大并发数
+	declPos       map[any]token.Position            // 解析结果对应的声明位置
+	diagnostics   []Diagnostic
 }
 
 // NewGoSrcParser 创建一个新的 GoParser 实例
 func NewGoSrcParser() *GoSrcParser {
 	return &GoSrcParser{
-		typeNodes: make([]*ast.TypeSpec, 0),
-		funcNodes: make([]*ast.FuncDecl, 0),
-		structs:   make([]*models.GoStructType, 0),
-		funcs:     make([]*models.GoFuncType, 0),
+		nodePkgs:      make(map[ast.Node]*packages.Package),
+		typeNodes:     make([]*ast.TypeSpec, 0),
+		funcNodes:     make([]*ast.FuncDecl, 0),
+		structs:       make([]*models.GoStructType, 0),
+		enums:         make([]*models.GoEnumType, 0),
+		handles:       make([]*models.GoHandleType, 0),
+		errors:        make([]*models.GoErrorType, 0),
+		funcs:         make([]*models.GoFuncType, 0),
+		imports:       make(map[string]string),
+		importedTypes: make(map[string]bool),
+		enumTypes:     make(map[string]*models.GoEnumType),
+		namedTypes:    make(map[string]*models.GoNamedType),
+		handleTypes:   make(map[string]*models.GoHandleType),
+		declPos:       make(map[any]token.Position),
 	}
 }
 
@@ -56,14 +90,20 @@ func (p *GoSrcParser) Parse(path string, ignoreFileNames []string) (*models.Pack
 	if err != nil {
 		return nil, err
 	}
+	p.module = module
 	p.ProjectNaming = models.NewProjectNaming(module)
-	p.CreateTimestampFile(".")
+	if p.ReadOnly {
+		p.LoadTimestampFile(".")
+	} else {
+		p.CreateTimestampFile(".")
+	}
 
 	// 加载包
 	pkgs, err := p.loadPackages(path, fileInfo)
 	if err != nil {
 		return nil, err
 	}
+	p.pkgs = pkgs
 
 	// 开始解析
 	log.Println(locales.MustLocalizeMessage(&i18n.Message{
@@ -83,8 +123,14 @@ func (p *GoSrcParser) Parse(path string, ignoreFileNames []string) (*models.Pack
 		ProjectNaming: p.ProjectNaming,
 		Module:        module,
 		PkgPath:       pkgPath,
+		Imports:       p.imports,
 		Structs:       p.structs,
+		Enums:         p.enums,
+		Named:         p.named,
+		Handles:       p.handles,
+		Errors:        p.errors,
 		Funcs:         p.funcs,
+		Concurrency:   p.concurrency,
 	}, nil
 }
 
@@ -93,7 +139,8 @@ func (p *GoSrcParser) loadPackages(path string, fileInfo os.FileInfo) ([]*packag
 	// 配置包加载
 	config := &packages.Config{
 		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles | packages.NeedImports |
-			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedTypesSizes,
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedTypesSizes |
+			packages.NeedDeps,
 	}
 
 	var pkgs []*packages.Package
@@ -130,7 +177,12 @@ func (p *GoSrcParser) parsePackages(pkgs []*packages.Package, ignoreFileNames []
 			ID:    "ffigen.srcparser.parse.package",
 			Other: " - 正在解析Package:",
 		}), pkg.Dir)
-		for i, file := range pkg.CompiledGoFiles {
+		for _, syntax := range pkg.Syntax {
+			// 包中存在cgo文件时，编译文件列表中会包含cgo生成或改写的文件，只解析源文件
+			file := pkg.Fset.PositionFor(syntax.Package, false).Filename
+			if !slices.Contains(pkg.GoFiles, file) {
+				continue
+			}
 			relPath, err := filepath.Rel(pkg.Dir, file)
 			if err != nil {
 				continue
@@ -145,8 +197,7 @@ func (p *GoSrcParser) parsePackages(pkgs []*packages.Package, ignoreFileNames []
 				ID:    "ffigen.srcparser.parse.file",
 				Other: "   - 正在解析文件:",
 			}), relPath)
-			syntax := pkg.Syntax[i]
-			if err := p.collectNodes(syntax); err != nil {
+			if err := p.collectNodes(pkg, syntax); err != nil {
 				return err
 			}
 		}
@@ -155,18 +206,26 @@ func (p *GoSrcParser) parsePackages(pkgs []*packages.Package, ignoreFileNames []
 }
 
 // collectNodes 从文件中收集 AST 节点
-func (p *GoSrcParser) collectNodes(file *ast.File) error {
+func (p *GoSrcParser) collectNodes(pkg *packages.Package, file *ast.File) error {
+	if err := p.processPackageConcurrency(file); err != nil {
+		return err
+	}
+
 	for _, decl := range file.Decls {
 		switch node := decl.(type) {
 		case *ast.GenDecl:
 			// 处理通用声明（IMPORT, CONST, TYPE, VAR）
-			if err := p.handleGenDecl(node); err != nil {
+			if err := p.handleGenDecl(pkg, node); err != nil {
 				return err
 			}
 
 		case *ast.FuncDecl:
-			// 收集函数声明
+			// 收集函数声明，跳过使用 //fgo:ignore 标记的函数
+			if hasDirective(node.Doc, "ignore") {
+				continue
+			}
 			p.funcNodes = append(p.funcNodes, node)
+			p.nodePkgs[node] = pkg
 
 		default:
 			return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
@@ -180,7 +239,7 @@ func (p *GoSrcParser) collectNodes(file *ast.File) error {
 }
 
 // handleGenDecl 处理通用声明
-func (p *GoSrcParser) handleGenDecl(decl *ast.GenDecl) error {
+func (p *GoSrcParser) handleGenDecl(pkg *packages.Package, decl *ast.GenDecl) error {
 	switch decl.Tok {
 	case token.IMPORT, token.CONST, token.VAR:
 		// 忽略这些声明
@@ -195,7 +254,13 @@ func (p *GoSrcParser) handleGenDecl(decl *ast.GenDecl) error {
 			return nil
 		}
 
+		// 跳过使用 //fgo:ignore 标记的类型
+		if hasDirective(decl.Doc, "ignore") || hasDirective(typeSpec.Doc, "ignore") {
+			return nil
+		}
+
 		p.typeNodes = append(p.typeNodes, typeSpec)
+		p.nodePkgs[typeSpec] = pkg
 		return nil
 	default:
 		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
@@ -214,19 +279,39 @@ func (p *GoSrcParser) processNodes() error {
 
 	// 处理类型
 	for _, spec := range p.typeNodes {
+		p.curPkg = p.nodePkgs[spec]
 		if err := p.processTypeNode(spec); err != nil {
-			return err
+			if err := p.report(spec.Name.Name, p.declError(spec, spec.Name.Name, err)); err != nil {
+				return err
+			}
 		}
 	}
 
 	// 处理函数
 	for _, decl := range p.funcNodes {
+		p.curPkg = p.nodePkgs[decl]
 		if err := p.processFunctionNode(decl); err != nil {
-			return err
+			name := funcDeclName(decl)
+			if err := p.report(name, p.declError(decl, name, err)); err != nil {
+				return err
+			}
 		}
 	}
 
-	return nil
+	// 移除引用了被跳过类型的声明
+	return p.pruneUnresolved()
+}
+
+// funcDeclName 返回函数声明的名称，方法包含接收者类型名
+func funcDeclName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return decl.Name.Name
+	}
+	recv := decl.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	return types.ExprString(recv) + "." + decl.Name.Name
 }
 
 // processTypeNode 处理类型声明
@@ -238,19 +323,66 @@ func (p *GoSrcParser) processTypeNode(typeSpec *ast.TypeSpec) error {
 		return nil
 	}
 
+	// 泛型类型需要通过类型别名实例化后使用，如 type IntPage = Page[int]
+	if typeSpec.TypeParams != nil {
+		log.Println(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.generic.skip",
+			Other: " - 跳过泛型类型, 可通过类型别名实例化后使用:",
+		}), name)
+		return nil
+	}
+
 	log.Println(locales.MustLocalizeMessage(&i18n.Message{
 		ID:    "ffigen.srcparser.process.type.info",
 		Other: " - 正在解析类型:",
 	}), name)
+	defer p.enterDecl(name)()
 
-	if typeSpec.TypeParams != nil {
-		return errors.New(locales.MustLocalizeMessage(&i18n.Message{
-			ID:    "ffigen.srcparser.process.type.error",
-			Other: "不支持泛型类型参数",
-		}))
+	if obj, ok := p.curPkg.Types.Scope().Lookup(name).(*types.TypeName); ok {
+		// 处理实现了error接口的错误类型
+		if p.isErrorImpl(obj) {
+			return p.parseErrorType(obj)
+		}
+
+		// 处理由命名基础类型和常量定义的枚举
+		if isEnumType(obj) {
+			_, err := p.parseEnumType(obj)
+			return err
+		}
+
+		// 处理以句柄形式传递的类型
+		if p.isHandleType(obj) {
+			_, err := p.parseHandleType(obj)
+			return err
+		}
+
+		// 处理以底层类型传递的命名类型
+		if isNamedType(obj) {
+			_, err := p.parseNamedType(obj)
+			return err
+		}
+
+		// 类型别名在使用时解析为其指向的类型
+		if obj.IsAlias() && !isInstantiation(typeSpec.Type) {
+			return nil
+		}
+
+		if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+			log.Println(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.type.skip",
+				Other: " - 跳过无法桥接的类型:",
+			}), name)
+			return nil
+		}
 	}
 
-	goType, err := p.parseTypeExpr(name, typeSpec.Type)
+	var goType models.GoType
+	var err error
+	if typeSpec.Assign.IsValid() && isInstantiation(typeSpec.Type) {
+		goType, err = p.parseInstantiatedType(name, typeSpec.Type)
+	} else {
+		goType, err = p.parseTypeExpr(name, typeSpec.Type)
+	}
 	if err != nil {
 		return err
 	}
@@ -263,15 +395,138 @@ func (p *GoSrcParser) processTypeNode(typeSpec *ast.TypeSpec) error {
 		}), reflect.TypeOf(goType))
 	}
 
+	if err := p.checkNameConflict(structType); err != nil {
+		return err
+	}
+
+	p.declPos[structType] = p.curPkg.Fset.Position(typeSpec.Pos())
 	p.structs = append(p.structs, structType)
 	return nil
 }
 
+// isInstantiation 判断类型表达式是否为泛型类型的实例化
+func isInstantiation(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	}
+	return false
+}
+
+// parseInstantiatedType 将泛型结构体的实例化别名展开为具体的结构体
+// 在泛型类型所在的包中解析其字段，字段中的类型参数替换为别名中的类型实参
+func (p *GoSrcParser) parseInstantiatedType(name string, expr ast.Expr) (models.GoType, error) {
+	var indices []ast.Expr
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		indices = []ast.Expr{e.Index}
+	case *ast.IndexListExpr:
+		indices = e.Indices
+	}
+
+	named, ok := types.Unalias(p.curPkg.TypesInfo.TypeOf(expr)).(*types.Named)
+	if !ok || named.TypeArgs().Len() != len(indices) {
+		return nil, genericUnsupportedError(expr)
+	}
+
+	origin := named.Origin()
+	obj := origin.Obj()
+	if obj.Pkg() == nil || !p.isModulePkg(obj.Pkg()) {
+		return nil, genericUnsupportedError(expr)
+	}
+	pkg := p.findPackage(obj.Pkg().Path())
+	if pkg == nil {
+		return nil, genericUnsupportedError(expr)
+	}
+	typeSpec, _ := findTypeSpec(pkg, obj.Name())
+	if typeSpec == nil {
+		return nil, genericUnsupportedError(expr)
+	}
+	structAst, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return nil, genericUnsupportedError(expr)
+	}
+
+	// 类型实参在别名所在的包中解析
+	typeArgs := make(map[*types.TypeName]models.GoType, len(p.typeArgs)+len(indices))
+	for param, arg := range p.typeArgs {
+		typeArgs[param] = arg
+	}
+	for i, index := range indices {
+		argType, err := p.parseTypeExpr("", index)
+		if err != nil {
+			return nil, err
+		}
+		typeArgs[origin.TypeParams().At(i).Obj()] = argType
+	}
+
+	curPkg, curArgs := p.curPkg, p.typeArgs
+	p.curPkg, p.typeArgs = pkg, typeArgs
+	goType, err := p.parseTypeExpr(name, structAst)
+	p.curPkg, p.typeArgs = curPkg, curArgs
+	if err != nil {
+		return nil, err
+	}
+
+	// 生成的代码通过别名引用实例化后的类型
+	structType := goType.(*models.GoStructType)
+	structType.Type = &models.GoIdentType{
+		Name: name,
+		Pkg:  p.qualifier(p.curPkg.Types),
+	}
+	return structType, nil
+}
+
+// genericUnsupportedError 返回无法展开泛型类型时的错误
+func genericUnsupportedError(expr ast.Expr) error {
+	return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.generic.unsupported",
+		Other: "只支持通过类型别名实例化当前模块中的泛型结构体, 如 type IntPage = Page[int]: %s",
+	}), types.ExprString(expr))
+}
+
+// namedType 可以检查名称冲突的类型
+type namedType interface {
+	String() string
+	GoType() string
+}
+
+// checkNameConflict 检查类型名称是否与已解析的结构体、枚举、句柄或错误类型冲突
+// 不同包中的同名类型在C和Dart中会产生冲突
+func (p *GoSrcParser) checkNameConflict(goType namedType) error {
+	exists := make([]namedType, 0, len(p.structs)+len(p.enums)+len(p.named)+len(p.handles)+len(p.errors))
+	for _, exist := range p.structs {
+		exists = append(exists, exist)
+	}
+	for _, exist := range p.enums {
+		exists = append(exists, exist)
+	}
+	for _, exist := range p.named {
+		exists = append(exists, exist)
+	}
+	for _, exist := range p.handles {
+		exists = append(exists, exist)
+	}
+	for _, exist := range p.errors {
+		exists = append(exists, exist)
+	}
+
+	for _, exist := range exists {
+		if exist.String() == goType.String() {
+			return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.struct.conflict",
+				Other: "类型名称冲突: %s 与 %s",
+			}), exist.GoType(), goType.GoType())
+		}
+	}
+	return nil
+}
+
 // processFunctionNode 处理函数声明
 func (p *GoSrcParser) processFunctionNode(funcDecl *ast.FuncDecl) error {
-	// 跳过方法（带接收者的函数）
+	// 处理方法（带接收者的函数）
 	if funcDecl.Recv != nil {
-		return nil
+		return p.processMethodNode(funcDecl)
 	}
 
 	name := funcDecl.Name.Name
@@ -280,12 +535,23 @@ func (p *GoSrcParser) processFunctionNode(funcDecl *ast.FuncDecl) error {
 		return nil
 	}
 
+	// 泛型函数无法直接导出到C
+	if funcDecl.Type.TypeParams != nil {
+		log.Println(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.generic.func",
+			Other: " - 跳过泛型函数:",
+		}), name)
+		return nil
+	}
+
 	log.Println(locales.MustLocalizeMessage(&i18n.Message{
 		ID:    "ffigen.srcparser.process.func.info",
 		Other: " - 正在解析函数:",
 	}), name)
+	defer p.enterDecl(name)()
 
-	goType, err := p.parseTypeExpr(name, funcDecl.Type)
+	funcAst, hasContext := p.splitContextParam(funcDecl.Type)
+	goType, err := p.parseTypeExpr(name, funcAst)
 	if err != nil {
 		return err
 	}
@@ -297,16 +563,156 @@ func (p *GoSrcParser) processFunctionNode(funcDecl *ast.FuncDecl) error {
 			Other: "预期为函数类型, 但得到 %v",
 		}), reflect.TypeOf(goType))
 	}
+	funcType.HasContext = hasContext
 
 	// 处理函数返回值
 	processFunctionReturnValues(funcType)
+	if err := processStreamResult(funcType); err != nil {
+		return err
+	}
+	if err := processZeroCopy(funcDecl, funcType); err != nil {
+		return err
+	}
+	if err := processConcurrency(funcDecl, funcType); err != nil {
+		return err
+	}
+
+	p.declPos[funcType] = p.curPkg.Fset.Position(funcDecl.Pos())
+	p.funcs = append(p.funcs, funcType)
+	return nil
+}
+
+// processMethodNode 处理结构体方法声明
+// 只导出当前包中已导出结构体的导出方法，其他类型的方法将被跳过
+func (p *GoSrcParser) processMethodNode(funcDecl *ast.FuncDecl) error {
+	if !funcDecl.Name.IsExported() || len(funcDecl.Recv.List) != 1 {
+		return nil
+	}
+
+	recvExpr := funcDecl.Recv.List[0].Type
+	star, ptrRecv := recvExpr.(*ast.StarExpr)
+	if ptrRecv {
+		recvExpr = star.X
+	}
+
+	// 泛型类型的方法接收者不是标识符
+	ident, ok := recvExpr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	var recvStruct *models.GoStructType
+	for _, structType := range p.structs {
+		if identType, ok := structType.Type.(*models.GoIdentType); ok && identType.Pkg == "" && identType.Name == ident.Name {
+			recvStruct = structType
+			break
+		}
+	}
+
+	var recvHandle *models.GoHandleType
+	for _, handleType := range p.handles {
+		if handleType.Pkg == "" && handleType.Name == ident.Name {
+			recvHandle = handleType
+			break
+		}
+	}
+
+	if recvStruct == nil && recvHandle == nil {
+		return nil
+	}
+
+	method := funcDecl.Name.Name
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.method.info",
+		Other: " - 正在解析方法:",
+	}), ident.Name+"."+method)
+	defer p.enterDecl(ident.Name + "." + method)()
+
+	funcAst, hasContext := p.splitContextParam(funcDecl.Type)
+	goType, err := p.parseTypeExpr(ident.Name+"."+method, funcAst)
+	if err != nil {
+		return err
+	}
+	funcType := goType.(*models.GoFuncType)
+	funcType.HasContext = hasContext
+
+	// 接收者作为第一个参数传递，结构体的指针接收者额外传递对象句柄
+	var recvFields []*models.GoField
+	if recvHandle != nil {
+		recvFields = []*models.GoField{{Name: "FgRecv", Type: recvHandle}}
+		funcType.Recv = recvHandle
+	} else {
+		recvFields = []*models.GoField{{Name: "FgRecv", Type: recvStruct.Type}}
+		if ptrRecv {
+			recvFields = append(recvFields, &models.GoField{Name: "FgHandle", Type: models.BasicTypeMap["uintptr"]})
+		}
+		funcType.Recv = recvStruct
+		funcType.PtrRecv = ptrRecv
+	}
+	funcType.Params.Fields = append(recvFields, funcType.Params.Fields...)
+	funcType.Method = method
+
+	processFunctionReturnValues(funcType)
+	if err := processStreamResult(funcType); err != nil {
+		return err
+	}
+	if err := processZeroCopy(funcDecl, funcType); err != nil {
+		return err
+	}
+	if err := processConcurrency(funcDecl, funcType); err != nil {
+		return err
+	}
 
+	// 解析成功后才加入接收者的方法列表，避免诊断模式下跳过的方法残留
+	if recvHandle != nil {
+		recvHandle.Methods = append(recvHandle.Methods, funcType)
+	} else {
+		recvStruct.Methods = append(recvStruct.Methods, funcType)
+	}
+	p.declPos[funcType] = p.curPkg.Fset.Position(funcDecl.Pos())
 	p.funcs = append(p.funcs, funcType)
 	return nil
 }
 
-// parseTypeExpr 解析 Go 类型表达式
+// splitContextParam 分离函数第一个 context.Context 类型的参数
+// 返回不含该参数的函数类型，调用时由生成的代码创建context并在Dart端取消时取消
+func (p *GoSrcParser) splitContextParam(funcType *ast.FuncType) (*ast.FuncType, bool) {
+	if funcType.Params == nil || len(funcType.Params.List) == 0 {
+		return funcType, false
+	}
+
+	first := funcType.Params.List[0]
+	if len(first.Names) > 1 || !isContextType(p.curPkg.TypesInfo.TypeOf(first.Type)) {
+		return funcType, false
+	}
+
+	params := *funcType.Params
+	params.List = params.List[1:]
+	result := *funcType
+	result.Params = &params
+	return &result, true
+}
+
+// isContextType 判断是否为 context.Context 类型
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// parseTypeExpr 解析 Go 类型表达式，错误中包含表达式的源码位置
 func (p *GoSrcParser) parseTypeExpr(name string, expr ast.Expr) (models.GoType, error) {
+	goType, err := p.parseTypeNode(name, expr)
+	if err != nil {
+		return nil, p.wrapError(expr, err)
+	}
+	return goType, nil
+}
+
+// parseTypeNode 按表达式的语法类型解析 Go 类型
+func (p *GoSrcParser) parseTypeNode(name string, expr ast.Expr) (models.GoType, error) {
 	switch e := expr.(type) {
 	case *ast.Ident:
 		// 处理基本类型
@@ -321,23 +727,103 @@ func (p *GoSrcParser) parseTypeExpr(name string, expr ast.Expr) (models.GoType,
 			}))
 		}
 
+		obj, ok := p.curPkg.TypesInfo.Uses[e].(*types.TypeName)
+		if argType := p.typeArgs[obj]; ok && argType != nil {
+			// 展开泛型结构体时使用类型实参替换类型参数
+			return argType, nil
+		}
+		if ok && obj.Pkg() != nil {
+			if p.isIgnoredType(obj) {
+				return nil, ignoredTypeError(obj)
+			}
+
+			if obj.IsAlias() {
+				return p.parseAliasType(obj)
+			}
+
+			// 错误类型只能通过error返回值传递
+			if p.isErrorImpl(obj) {
+				return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+					ID:    "ffigen.srcparser.process.errortype.byvalue",
+					Other: "错误类型 %s 只能通过error返回值传递",
+				}), obj.Name())
+			}
+
+			// 处理枚举类型
+			if isEnumType(obj) {
+				return p.parseEnumType(obj)
+			}
+
+			if p.isHandleType(obj) {
+				return nil, handleByValueError(obj)
+			}
+
+			if isNamedType(obj) {
+				return p.parseNamedType(obj)
+			}
+
+			// 在导入包中引用的同包类型也需要导入
+			if !p.isRootPkg(obj.Pkg()) {
+				return p.parseImportedType(obj)
+			}
+		}
+
 		return &models.GoIdentType{
 			Name: e.Name,
 		}, nil
 
 	case *ast.SelectorExpr:
-		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
-			ID:    "ffigen.srcparser.process.selector.unsupported",
-			Other: "不支持导入类型: %v.%v",
-		}), e.X, e.Sel)
+		obj, ok := p.curPkg.TypesInfo.Uses[e.Sel].(*types.TypeName)
+
+		// 处理内置映射的导入类型, 如 time.Time
+		if ok && obj.Pkg() != nil {
+			if basicType := models.BasicTypeMap[obj.Pkg().Path()+"."+obj.Name()]; basicType != nil {
+				p.imports[obj.Pkg().Path()] = obj.Pkg().Name()
+				return basicType, nil
+			}
+		}
+
+		// 处理模块内其他包中的类型
+		if !ok || obj.Pkg() == nil || !p.isModulePkg(obj.Pkg()) {
+			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.selector.unsupported",
+				Other: "不支持导入类型: %s, 只支持当前模块中的包",
+			}), types.ExprString(e))
+		}
+		if p.isIgnoredType(obj) {
+			return nil, ignoredTypeError(obj)
+		}
+		if obj.IsAlias() {
+			return p.parseAliasType(obj)
+		}
+		if isEnumType(obj) {
+			return p.parseEnumType(obj)
+		}
+		if p.isHandleType(obj) {
+			return nil, handleByValueError(obj)
+		}
+		if isNamedType(obj) {
+			return p.parseNamedType(obj)
+		}
+
+		return p.parseImportedType(obj)
 
 	case *ast.StarExpr:
+		// 句柄类型的指针以句柄形式传递
+		if obj := p.lookupTypeName(e.X); obj != nil && obj.Pkg() != nil && p.isHandleType(obj) {
+			return p.parseHandleType(obj)
+		}
+
 		// 处理指针类型
 		inner, err := p.parseTypeExpr("", e.X)
 		if err != nil {
 			return nil, err
 		}
 
+		if err := checkInnerType(inner); err != nil {
+			return nil, err
+		}
+
 		switch inner.(type) {
 		case *models.GoPointerType:
 			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
@@ -351,19 +837,29 @@ func (p *GoSrcParser) parseTypeExpr(name string, expr ast.Expr) (models.GoType,
 		}, nil
 
 	case *ast.ArrayType:
-		// 处理切片类型
-		if e.Len != nil {
-			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
-				ID:    "ffigen.srcparser.process.array.unsupported",
-				Other: "不支持固定大小的数组: %v",
-			}), e.Len)
-		}
-
 		inner, err := p.parseTypeExpr("", e.Elt)
 		if err != nil {
 			return nil, err
 		}
 
+		if err := checkInnerType(inner); err != nil {
+			return nil, err
+		}
+
+		// 处理固定大小的数组类型
+		if e.Len != nil {
+			length, err := p.parseArrayLen(e.Len)
+			if err != nil {
+				return nil, err
+			}
+
+			return &models.GoArrayType{
+				Inner: inner,
+				Len:   length,
+			}, nil
+		}
+
+		// 处理切片类型
 		if inner.GoType() == "byte" {
 			return models.BasicTypeMap["[]byte"], nil
 		}
@@ -372,6 +868,61 @@ func (p *GoSrcParser) parseTypeExpr(name string, expr ast.Expr) (models.GoType,
 			Inner: inner,
 		}, nil
 
+	case *ast.MapType:
+		// 处理Map类型
+		key, err := p.parseTypeExpr("", e.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isMapKeyType(key) {
+			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.mapkey.unsupported",
+				Other: "不支持的Map键类型: %v",
+			}), key)
+		}
+
+		value, err := p.parseTypeExpr("", e.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkInnerType(value); err != nil {
+			return nil, err
+		}
+
+		return &models.GoMapType{
+			Key:   key,
+			Value: value,
+		}, nil
+
+	case *ast.ChanType:
+		// 处理通道类型，只支持可接收的通道
+		if e.Dir == ast.SEND {
+			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.sendchan.unsupported",
+				Other: "不支持只写通道: %v",
+			}), types.ExprString(e))
+		}
+
+		inner, err := p.parseTypeExpr("", e.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkInnerType(inner); err != nil {
+			return nil, err
+		}
+
+		dir := models.ChanBoth
+		if e.Dir == ast.RECV {
+			dir = models.ChanRecv
+		}
+		return &models.GoChanType{
+			Inner: inner,
+			Dir:   dir,
+		}, nil
+
 	case *ast.StructType:
 		// 处理结构类型
 		fields, err := p.parseFields(e.Fields, true)
@@ -386,20 +937,29 @@ func (p *GoSrcParser) parseTypeExpr(name string, expr ast.Expr) (models.GoType,
 			}), name)
 		}
 
+		if err := checkDartFieldNames(name, fields); err != nil {
+			return nil, err
+		}
+
 		return &models.GoStructType{
 			Type: &models.GoIdentType{
 				Name: name,
+				Pkg:  p.qualifier(p.curPkg.Types),
 			},
 			Fields: fields,
 		}, nil
 
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// 泛型类型的实例化只能通过类型别名展开
+		return nil, genericUnsupportedError(e)
+
 	case *ast.FuncType:
 		// 处理函数类型
 		if e.TypeParams != nil {
 			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
 				ID:    "ffigen.srcparser.process.func.unsupported",
-				Other: "不支持泛型函数: %v",
-			}), e.TypeParams)
+				Other: "不支持泛型函数: %s",
+			}), types.ExprString(e))
 		}
 
 		params, err := p.parseFields(e.Params, false)
@@ -412,23 +972,629 @@ func (p *GoSrcParser) parseTypeExpr(name string, expr ast.Expr) (models.GoType,
 			return nil, err
 		}
 
-		return &models.GoFuncType{
-			Name: name,
-			Params: &models.GoStructType{
-				Type:   &models.GoIdentType{Name: strcase.ToLowerCamel(name) + "Params"},
-				Fields: params,
-			},
-			Results: &models.GoStructType{
-				Type:   &models.GoIdentType{Name: strcase.ToLowerCamel(name) + "Results"},
-				Fields: results,
-			},
-		}, nil
-	default:
+		// 参数或字段中的函数类型作为Dart回调，不支持返回值和嵌套的回调
+		if name == "" {
+			if len(results) > 0 {
+				return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+					ID:    "ffigen.srcparser.process.callback.results",
+					Other: "回调函数不支持返回值: %v",
+				}), types.ExprString(e))
+			}
+			for i, field := range params {
+				switch field.Type.(type) {
+				case *models.GoCallbackType:
+					return nil, callbackUnsupportedError(types.ExprString(e))
+				case *models.GoChanType:
+					return nil, chanUnsupportedError(field.Type)
+				}
+				if field.Name == "" {
+					field.Name = fmt.Sprintf("arg%d", i)
+				}
+			}
+			return &models.GoCallbackType{
+				Args: &models.GoStructType{Fields: params},
+			}, nil
+		}
+
+		for _, field := range results {
+			if _, ok := field.Type.(*models.GoCallbackType); ok {
+				return nil, callbackUnsupportedError(field.Type)
+			}
+		}
+
+		// 方法的名称为 接收者.方法名，生成的结构体和回调名称以接收者为作用域
+		scope, base, ok := strings.Cut(name, ".")
+		if !ok {
+			scope, base = "", name
+		}
+
+		// 为每个回调参数命名，同一声明中的多个参数共享解析结果，需要分别创建
+		for _, field := range params {
+			if callbackType, ok := field.Type.(*models.GoCallbackType); ok {
+				callbackName := base + strcase.ToCamel(field.Name)
+				field.Type = &models.GoCallbackType{
+					Name:  callbackName,
+					Scope: scope,
+					Args: &models.GoStructType{
+						Type:   &models.GoIdentType{Name: strcase.ToLowerCamel(callbackName) + "Args", Scope: scope},
+						Fields: callbackType.Args.Fields,
+					},
+				}
+			}
+		}
+
+		return &models.GoFuncType{
+			Name: name,
+			Params: &models.GoStructType{
+				Type:   &models.GoIdentType{Name: strcase.ToLowerCamel(base) + "Params", Scope: scope},
+				Fields: params,
+			},
+			Results: &models.GoStructType{
+				Type:   &models.GoIdentType{Name: strcase.ToLowerCamel(base) + "Results", Scope: scope},
+				Fields: results,
+			},
+		}, nil
+	default:
 		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
 			ID:    "ffigen.srcparser.process.type.unsupported",
-			Other: "不支持该类型: %v (%T)",
-		}), expr, expr)
+			Other: "不支持该类型: %s (%T)",
+		}), types.ExprString(expr), expr)
+	}
+}
+
+// parseImportedType 解析模块内其他包中的类型，并将其结构体定义加入生成列表
+func (p *GoSrcParser) parseImportedType(obj *types.TypeName) (models.GoType, error) {
+	if !obj.Exported() {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.imported.unexported",
+			Other: "不支持未导出的导入类型: %s.%s",
+		}), obj.Pkg().Path(), obj.Name())
+	}
+
+	pkgPath := obj.Pkg().Path()
+	identType := &models.GoIdentType{
+		Name: obj.Name(),
+		Pkg:  obj.Pkg().Name(),
+	}
+
+	key := pkgPath + "." + obj.Name()
+	if p.importedTypes[key] {
+		return identType, nil
+	}
+	p.importedTypes[key] = true
+	p.imports[pkgPath] = obj.Pkg().Name()
+
+	// 查找类型定义所在的包及其声明
+	pkg := p.findPackage(pkgPath)
+	var typeSpec *ast.TypeSpec
+	if pkg != nil {
+		typeSpec, _ = findTypeSpec(pkg, obj.Name())
+	}
+	if typeSpec == nil {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.imported.notfound",
+			Other: "未找到导入类型的定义: %s",
+		}), key)
+	}
+
+	// 在类型所在的包中解析其定义
+	curPkg := p.curPkg
+	p.curPkg = pkg
+	err := p.processTypeNode(typeSpec)
+	p.curPkg = curPkg
+	if err != nil {
+		// 解析失败的类型在再次引用时重新报告错误
+		delete(p.importedTypes, key)
+		return nil, err
+	}
+
+	return identType, nil
+}
+
+// isMapKeyType 判断类型是否可作为Map的键，只支持基础类型和枚举
+func isMapKeyType(t models.GoType) bool {
+	switch t := t.(type) {
+	case *models.GoEnumType:
+		return true
+	case *models.GoNamedType:
+		return isMapKeyType(t.Underlying)
+	case *models.GoBasicType:
+		return t.GoType() != "error" && t.GoType() != "[]byte"
+	}
+	return false
+}
+
+// isEnumType 判断是否为可作为枚举的类型，即底层为整数或字符串且声明了同类型导出常量的命名类型
+func isEnumType(obj *types.TypeName) bool {
+	if obj.IsAlias() {
+		return false
+	}
+	basic, ok := obj.Type().Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsInteger|types.IsString) != 0 && len(enumConsts(obj)) > 0
+}
+
+// enumConsts 按声明顺序返回类型所在包中声明的同类型导出常量
+func enumConsts(obj *types.TypeName) []*types.Const {
+	scope := obj.Pkg().Scope()
+	consts := make([]*types.Const, 0)
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && c.Exported() && types.Identical(c.Type(), obj.Type()) {
+			consts = append(consts, c)
+		}
+	}
+	slices.SortFunc(consts, func(a, b *types.Const) int {
+		return int(a.Pos() - b.Pos())
+	})
+	return consts
+}
+
+// parseEnumType 解析枚举类型，枚举值为类型所在包中声明的同类型导出常量
+func (p *GoSrcParser) parseEnumType(obj *types.TypeName) (models.GoType, error) {
+	key := obj.Pkg().Path() + "." + obj.Name()
+	if enumType := p.enumTypes[key]; enumType != nil {
+		return enumType, nil
+	}
+
+	if !obj.Exported() {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.enum.unexported",
+			Other: "不支持未导出的枚举类型: %s",
+		}), key)
+	}
+
+	basic := obj.Type().Underlying().(*types.Basic)
+	enumType := &models.GoEnumType{
+		Name: obj.Name(),
+		Pkg:  p.qualifier(obj.Pkg()),
+		Base: models.BasicTypeMap[basic.Name()],
+	}
+
+	for _, c := range enumConsts(obj) {
+		if enumType.IsString() {
+			enumType.AddValue(c.Name(), constant.StringVal(c.Val()))
+		} else {
+			enumType.AddValue(c.Name(), c.Val().ExactString())
+		}
+	}
+
+	if enumType.Base == nil || len(enumType.Values) == 0 {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.enum.novalues",
+			Other: "类型 %s 未定义任何导出常量, 无法作为枚举使用",
+		}), key)
+	}
+
+	if err := p.checkNameConflict(enumType); err != nil {
+		return nil, err
+	}
+
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.enum.info",
+		Other: " - 正在解析枚举:",
+	}), obj.Name())
+
+	if enumType.Pkg != "" {
+		p.imports[obj.Pkg().Path()] = obj.Pkg().Name()
+	}
+	p.enumTypes[key] = enumType
+	p.enums = append(p.enums, enumType)
+	return enumType, nil
+}
+
+// isIgnoredType 判断模块内的类型是否使用 //fgo:ignore 标记
+func (p *GoSrcParser) isIgnoredType(obj *types.TypeName) bool {
+	if !p.isModulePkg(obj.Pkg()) {
+		return false
+	}
+	pkg := p.findPackage(obj.Pkg().Path())
+	if pkg == nil {
+		return false
+	}
+	typeSpec, doc := findTypeSpec(pkg, obj.Name())
+	return typeSpec != nil && (hasDirective(doc, "ignore") || hasDirective(typeSpec.Doc, "ignore"))
+}
+
+// ignoredTypeError 返回引用被忽略的类型时的错误
+func ignoredTypeError(obj *types.TypeName) error {
+	return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.ignored",
+		Other: "类型 %s 已使用 //fgo:ignore 忽略, 不能在导出的声明中使用",
+	}), obj.Name())
+}
+
+// isNamedType 判断是否为以底层类型传递的命名类型，即底层为基础类型、切片或Map的非别名类型
+func isNamedType(obj *types.TypeName) bool {
+	if obj.IsAlias() {
+		return false
+	}
+	switch obj.Type().Underlying().(type) {
+	case *types.Basic, *types.Slice, *types.Map:
+		return true
+	}
+	return false
+}
+
+// parseNamedType 解析命名类型，在类型所在的包中解析其底层类型
+// 使用 //fgo:extension 标记的类型在Dart中生成 extension type
+func (p *GoSrcParser) parseNamedType(obj *types.TypeName) (models.GoType, error) {
+	key := obj.Pkg().Path() + "." + obj.Name()
+	if namedType, ok := p.namedTypes[key]; ok {
+		if namedType.Underlying == nil {
+			return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.named.recursive",
+				Other: "不支持递归定义的命名类型: %s",
+			}), key)
+		}
+		return namedType, nil
+	}
+
+	if !obj.Exported() {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.named.unexported",
+			Other: "不支持未导出的命名类型: %s",
+		}), key)
+	}
+
+	pkg := p.findPackage(obj.Pkg().Path())
+	var typeSpec *ast.TypeSpec
+	var doc *ast.CommentGroup
+	if pkg != nil {
+		typeSpec, doc = findTypeSpec(pkg, obj.Name())
+	}
+	if typeSpec == nil {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.named.notfound",
+			Other: "未找到命名类型的定义: %s",
+		}), key)
+	}
+
+	namedType := &models.GoNamedType{
+		Name:      obj.Name(),
+		Pkg:       p.qualifier(obj.Pkg()),
+		Extension: hasDirective(doc, "extension"),
+	}
+
+	// 解析底层类型时通过未设置底层类型的占位检测递归定义
+	p.namedTypes[key] = namedType
+	curPkg := p.curPkg
+	p.curPkg = pkg
+	restoreDecl := p.enterDecl(obj.Name())
+	underlying, err := p.parseTypeExpr("", typeSpec.Type)
+	restoreDecl()
+	p.curPkg = curPkg
+	if err != nil {
+		delete(p.namedTypes, key)
+		return nil, err
+	}
+
+	switch underlying.(type) {
+	case *models.GoBasicType, *models.GoSliceType, *models.GoMapType, *models.GoEnumType, *models.GoNamedType:
+		namedType.Underlying = underlying
+	default:
+		delete(p.namedTypes, key)
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.named.unsupported",
+			Other: "不支持命名类型 %s 的底层类型: %v",
+		}), key, underlying)
+	}
+
+	if err := p.checkNameConflict(namedType); err != nil {
+		delete(p.namedTypes, key)
+		return nil, err
+	}
+
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.named.info",
+		Other: " - 正在解析命名类型:",
+	}), obj.Name())
+
+	if namedType.Pkg != "" {
+		p.imports[obj.Pkg().Path()] = obj.Pkg().Name()
+	}
+	p.declPos[namedType] = pkg.Fset.Position(typeSpec.Pos())
+	p.named = append(p.named, namedType)
+	return namedType, nil
+}
+
+// parseAliasType 将类型别名解析为其指向的类型
+// 泛型实例化的别名已展开为同名结构体，按结构体引用
+func (p *GoSrcParser) parseAliasType(obj *types.TypeName) (models.GoType, error) {
+	pkg := p.findPackage(obj.Pkg().Path())
+	var typeSpec *ast.TypeSpec
+	if pkg != nil {
+		typeSpec, _ = findTypeSpec(pkg, obj.Name())
+	}
+
+	if typeSpec == nil || isInstantiation(typeSpec.Type) {
+		if p.isRootPkg(obj.Pkg()) {
+			return &models.GoIdentType{Name: obj.Name()}, nil
+		}
+		return p.parseImportedType(obj)
+	}
+
+	curPkg := p.curPkg
+	p.curPkg = pkg
+	restoreDecl := p.enterDecl(obj.Name())
+	goType, err := p.parseTypeExpr("", typeSpec.Type)
+	restoreDecl()
+	p.curPkg = curPkg
+	return goType, err
+}
+
+// findPackage 在已加载的包及其依赖中查找指定路径的包
+func (p *GoSrcParser) findPackage(pkgPath string) *packages.Package {
+	var found *packages.Package
+	packages.Visit(p.pkgs, func(pkg *packages.Package) bool {
+		if pkg.PkgPath == pkgPath {
+			found = pkg
+		}
+		return found == nil
+	}, nil)
+	return found
+}
+
+// findTypeSpec 在包的语法树中查找指定名称的类型声明及其文档注释
+// 单独声明的类型注释位于 GenDecl 上，分组声明的类型注释位于 TypeSpec 上
+func findTypeSpec(pkg *packages.Package, name string) (*ast.TypeSpec, *ast.CommentGroup) {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == name {
+					if typeSpec.Doc == nil && len(genDecl.Specs) == 1 {
+						return typeSpec, genDecl.Doc
+					}
+					return typeSpec, typeSpec.Doc
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// lookupTypeName 返回标识符或选择器表达式引用的类型名称
+func (p *GoSrcParser) lookupTypeName(expr ast.Expr) *types.TypeName {
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil
+	}
+
+	obj, _ := p.curPkg.TypesInfo.Uses[ident].(*types.TypeName)
+	return obj
+}
+
+// isHandleType 判断是否为以句柄形式传递的类型
+// 使用 //fgo:handle 标记的结构体，或只包含未导出字段的结构体，都不会按字段复制
+func (p *GoSrcParser) isHandleType(obj *types.TypeName) bool {
+	structType, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok || obj.IsAlias() {
+		return false
+	}
+
+	if pkg := p.findPackage(obj.Pkg().Path()); pkg != nil {
+		if _, doc := findTypeSpec(pkg, obj.Name()); hasDirective(doc, "handle") {
+			return true
+		}
+	}
+
+	return structType.NumFields() > 0 && !hasExportedFields(structType)
+}
+
+// hasExportedFields 判断结构体是否有导出字段，包括以值嵌入的结构体中提升的字段
+func hasExportedFields(structType *types.Struct) bool {
+	for i := range structType.NumFields() {
+		field := structType.Field(i)
+		if field.Exported() {
+			return true
+		}
+		if embedded, ok := field.Type().Underlying().(*types.Struct); ok && field.Embedded() && hasExportedFields(embedded) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHandleType 解析句柄类型，句柄类型可以来自任意包
+func (p *GoSrcParser) parseHandleType(obj *types.TypeName) (models.GoType, error) {
+	key := obj.Pkg().Path() + "." + obj.Name()
+	if handleType := p.handleTypes[key]; handleType != nil {
+		return handleType, nil
+	}
+
+	if !obj.Exported() {
+		return nil, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.handle.unexported",
+			Other: "不支持未导出的句柄类型: %s",
+		}), key)
+	}
+
+	handleType := &models.GoHandleType{
+		Name: obj.Name(),
+		Pkg:  p.qualifier(obj.Pkg()),
+	}
+	if err := p.checkNameConflict(handleType); err != nil {
+		return nil, err
+	}
+
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.handle.info",
+		Other: " - 正在解析句柄类型:",
+	}), obj.Name())
+
+	if handleType.Pkg != "" {
+		p.imports[obj.Pkg().Path()] = obj.Pkg().Name()
+	}
+	p.handleTypes[key] = handleType
+	p.handles = append(p.handles, handleType)
+	return handleType, nil
+}
+
+// handleByValueError 返回以值形式使用句柄类型时的错误
+func handleByValueError(obj *types.TypeName) error {
+	return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.handle.byvalue",
+		Other: "句柄类型 %s 只能以指针形式使用",
+	}), obj.Name())
+}
+
+// errorInterface 内置的error接口
+var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// dartExceptionMembers FgFfiException 中已定义的成员，错误类型中同名的字段无法生成到Dart的异常类中
+// 整数类型的 code 字段作为异常的错误码传递，其他同名字段被跳过并给出警告
+var dartExceptionMembers = []string{"message", "code", "chain", "hashCode", "runtimeType", "toString"}
+
+// isErrorImpl 判断是否为当前包中实现了error接口的类型
+func (p *GoSrcParser) isErrorImpl(obj *types.TypeName) bool {
+	if obj.IsAlias() || !p.isRootPkg(obj.Pkg()) || types.IsInterface(obj.Type()) {
+		return false
+	}
+	return types.Implements(obj.Type(), errorInterface) || types.Implements(types.NewPointer(obj.Type()), errorInterface)
+}
+
+// parseErrorType 解析错误类型，只有基础类型的导出字段会传递到Dart
+// 与 FgFfiException 成员同名的字段中，整数类型的 code 字段作为错误码传递，其他字段跳过并记录诊断
+func (p *GoSrcParser) parseErrorType(obj *types.TypeName) error {
+	errorType := &models.GoErrorType{
+		Name:    obj.Name(),
+		Pkg:     p.qualifier(obj.Pkg()),
+		PtrRecv: !types.Implements(obj.Type(), errorInterface),
+	}
+
+	if structType, ok := obj.Type().Underlying().(*types.Struct); ok {
+		for i := range structType.NumFields() {
+			field := structType.Field(i)
+			basic, ok := field.Type().(*types.Basic)
+			if !ok || !field.Exported() || field.Embedded() {
+				continue
+			}
+
+			tag, err := parseStructTag(structType.Tag(i))
+			if err != nil {
+				return err
+			}
+			if tag.omit {
+				continue
+			}
+
+			basicType := models.BasicTypeMap[basic.Name()]
+			if basicType == nil {
+				continue
+			}
+			goField := &models.GoField{Name: field.Name(), Type: basicType, Rename: tag.name}
+			if !slices.Contains(dartExceptionMembers, goField.DartName()) {
+				errorType.Fields = append(errorType.Fields, goField)
+				continue
+			}
+			if goField.DartName() == "code" && basic.Info()&types.IsInteger != 0 {
+				errorType.CodeField = goField
+				continue
+			}
+
+			name := obj.Name() + "." + field.Name()
+			if err := p.report(name, &ParseError{
+				Pos:  p.curPkg.Fset.Position(field.Pos()),
+				Decl: name,
+				Err: fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+					ID:    "ffigen.srcparser.process.errortype.member",
+					Other: "字段在Dart中的名称 %s 与 FgFfiException 的成员同名，不会传递到Dart，可以通过结构体标签重命名",
+				}), goField.DartName()),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := p.checkNameConflict(errorType); err != nil {
+		return err
+	}
+
+	log.Println(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.errortype.info",
+		Other: " - 正在解析错误类型:",
+	}), obj.Name())
+
+	p.errors = append(p.errors, errorType)
+	return nil
+}
+
+// isRootPkg 判断是否为gosrc/ffi目录中正在解析的包
+func (p *GoSrcParser) isRootPkg(pkg *types.Package) bool {
+	for _, root := range p.pkgs {
+		if root.PkgPath == pkg.Path() {
+			return true
+		}
+	}
+	return false
+}
+
+// isModulePkg 判断包是否属于当前模块
+func (p *GoSrcParser) isModulePkg(pkg *types.Package) bool {
+	return pkg.Path() == p.module || strings.HasPrefix(pkg.Path(), p.module+"/")
+}
+
+// qualifier 返回生成代码中引用该包中类型时使用的包名，当前包返回空字符串
+func (p *GoSrcParser) qualifier(pkg *types.Package) string {
+	if p.isRootPkg(pkg) {
+		return ""
+	}
+	return pkg.Name()
+}
+
+// parseArrayLen 解析固定大小数组的长度，支持字面量和常量表达式
+func (p *GoSrcParser) parseArrayLen(expr ast.Expr) (int, error) {
+	if tv, ok := p.curPkg.TypesInfo.Types[expr]; ok && tv.Value != nil {
+		if length, ok := constant.Int64Val(tv.Value); ok && length > 0 {
+			return int(length), nil
+		}
 	}
+
+	return 0, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.arraylen.invalid",
+		Other: "无效的数组长度: %s",
+	}), types.ExprString(expr))
+}
+
+// checkInnerType 检查类型是否可以作为切片、指针、Map或数组的元素
+// 固定大小的数组只能直接作为结构体字段、函数参数或返回值使用，通道只能作为函数返回值使用，回调只能作为函数参数使用
+func checkInnerType(inner models.GoType) error {
+	switch inner.(type) {
+	case *models.GoArrayType:
+		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.array.unsupported",
+			Other: "固定大小的数组只能直接作为字段、参数或返回值使用: %v",
+		}), inner)
+	case *models.GoChanType:
+		return chanUnsupportedError(inner)
+	case *models.GoCallbackType:
+		return callbackUnsupportedError(inner)
+	}
+	return nil
+}
+
+// chanUnsupportedError 返回在函数返回值以外的位置使用通道时的错误
+func chanUnsupportedError(chanType models.GoType) error {
+	return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.chan.unsupported",
+		Other: "通道只能作为函数的返回值使用: %v",
+	}), chanType)
+}
+
+// callbackUnsupportedError 返回在函数参数以外的位置使用函数类型时的错误
+func callbackUnsupportedError(funcType any) error {
+	return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+		ID:    "ffigen.srcparser.process.callback.unsupported",
+		Other: "函数类型只能作为函数的参数使用: %v",
+	}), funcType)
 }
 
 // parseFields 解析结构体、函数参数和结果的字段列表
@@ -437,30 +1603,26 @@ func (p *GoSrcParser) parseFields(list *ast.FieldList, isStruct bool) ([]*models
 		return nil, nil
 	}
 
+	if isStruct {
+		collected, err := p.collectStructFields(list)
+		if err != nil {
+			return nil, err
+		}
+		return promoteFields(collected), nil
+	}
+
 	fields := make([]*models.GoField, 0, len(list.List))
 	for _, field := range list.List {
 		var names []string
 		if field.Names == nil {
-			//结构体中过滤匿名字段
-			if isStruct {
-				continue
-			}
 			names = []string{""}
 		} else {
 			names = make([]string, 0, len(field.Names))
 			for _, name := range field.Names {
-				// 跳过私有的结构体字段
-				if isStruct && unicode.IsLower(rune(name.Name[0])) {
-					continue
-				}
 				names = append(names, name.Name)
 			}
 		}
 
-		if len(names) == 0 {
-			continue
-		}
-
 		fieldType, err := p.parseTypeExpr("", field.Type)
 		if err != nil {
 			return nil, err
@@ -477,6 +1639,264 @@ func (p *GoSrcParser) parseFields(list *ast.FieldList, isStruct bool) ([]*models
 	return fields, nil
 }
 
+// structField 结构体中收集到的字段，embedded 表示嵌入结构体字段本身或嵌入指针中的字段，只参与名称冲突检查
+type structField struct {
+	field    *models.GoField
+	embedded bool
+}
+
+// collectStructFields 收集结构体的导出字段以及嵌入结构体中的所有字段
+// 嵌入字段的 Embeds 记录其所在的嵌入结构体路径，由 promoteFields 按Go的提升规则处理
+func (p *GoSrcParser) collectStructFields(list *ast.FieldList) ([]structField, error) {
+	fields := make([]structField, 0, len(list.List))
+	for _, field := range list.List {
+		tag, err := parseFieldTag(field)
+		if err != nil {
+			return nil, p.wrapError(field, err)
+		}
+
+		if field.Names == nil {
+			embedded, err := p.collectEmbeddedFields(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			for i := range embedded {
+				// 排除的嵌入结构体中的字段仍参与名称冲突检查
+				embedded[i].embedded = embedded[i].embedded || tag.omit
+				embedded[i].field.Readonly = embedded[i].field.Readonly || tag.readonly
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+
+		var names []string
+		for _, name := range field.Names {
+			// 跳过私有的结构体字段
+			if name.IsExported() {
+				names = append(names, name.Name)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+
+		if tag.omit {
+			// 排除的字段不解析类型，但在Go中仍会与嵌入结构体中的同名字段冲突
+			for _, name := range names {
+				fields = append(fields, structField{field: &models.GoField{Name: name}, embedded: true})
+			}
+			continue
+		}
+
+		fieldType, err := p.parseTypeExpr("", field.Type)
+		if err != nil {
+			return nil, err
+		}
+		switch fieldType.(type) {
+		case *models.GoChanType:
+			return nil, p.wrapError(field.Type, chanUnsupportedError(fieldType))
+		case *models.GoCallbackType:
+			return nil, p.wrapError(field.Type, callbackUnsupportedError(fieldType))
+		}
+		for _, name := range names {
+			goField := &models.GoField{Name: name, Type: fieldType, Readonly: tag.readonly}
+			if len(names) == 1 {
+				// 同一声明中的多个字段不能使用同一个名称
+				goField.Rename = tag.name
+			}
+			fields = append(fields, structField{field: goField})
+		}
+	}
+	return fields, nil
+}
+
+// fieldTag 结构体字段的 fgo 标签，格式为 `fgo:"name,omit,readonly"`
+type fieldTag struct {
+	name     string // Dart字段名，为空时使用默认名称
+	omit     bool   // 不传递到Dart
+	readonly bool   // 在Dart中生成为final字段
+}
+
+// parseFieldTag 解析结构体字段声明中的标签
+func parseFieldTag(field *ast.Field) (fieldTag, error) {
+	if field.Tag == nil {
+		return fieldTag{}, nil
+	}
+	value, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return fieldTag{}, nil
+	}
+	return parseStructTag(value)
+}
+
+// parseStructTag 解析 fgo 标签，未指定名称时使用 json 标签中的名称
+func parseStructTag(value string) (fieldTag, error) {
+	var tag fieldTag
+	structTag := reflect.StructTag(value)
+
+	if fgoTag, ok := structTag.Lookup("fgo"); ok {
+		if fgoTag == "-" {
+			tag.omit = true
+			return tag, nil
+		}
+
+		name, opts, _ := strings.Cut(fgoTag, ",")
+		if opts != "" {
+			for _, opt := range strings.Split(opts, ",") {
+				switch strings.TrimSpace(opt) {
+				case "omit":
+					tag.omit = true
+				case "readonly":
+					tag.readonly = true
+				case "":
+				default:
+					return tag, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+						ID:    "ffigen.srcparser.process.tag.option",
+						Other: "未知的 fgo 标签选项 %q: %s",
+					}), opt, value)
+				}
+			}
+		}
+
+		if name != "" {
+			if !models.IsDartFieldName(name) {
+				return tag, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+					ID:    "ffigen.srcparser.process.tag.name",
+					Other: "fgo 标签中的名称 %q 不是有效的Dart字段名",
+				}), name)
+			}
+			tag.name = name
+			return tag, nil
+		}
+	}
+
+	// 与JSON序列化保持一致的字段名，不是有效的Dart字段名时使用默认名称
+	if jsonTag, ok := structTag.Lookup("json"); ok {
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if name != "-" && models.IsDartFieldName(name) {
+			tag.name = name
+		}
+	}
+	return tag, nil
+}
+
+// checkDartFieldNames 检查结构体字段在Dart中的名称是否冲突
+func checkDartFieldNames(structName string, fields []*models.GoField) error {
+	names := make(map[string]string, len(fields))
+	for _, field := range fields {
+		if other, ok := names[field.DartName()]; ok {
+			return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.tag.conflict",
+				Other: "结构体 %s 中的字段 %s 与 %s 在Dart中的名称 %s 冲突",
+			}), structName, field.Name, other, field.DartName())
+		}
+		names[field.DartName()] = field.Name
+	}
+	return nil
+}
+
+// collectEmbeddedFields 收集以值嵌入的模块内结构体的字段
+// 嵌入的指针、非结构体类型和模块外的类型不会展开，与之前忽略匿名字段的行为一致
+func (p *GoSrcParser) collectEmbeddedFields(expr ast.Expr) ([]structField, error) {
+	var name string
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	case *ast.StarExpr:
+		log.Println(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.embed.pointer",
+			Other: " - 跳过嵌入的结构体指针, 只支持以值嵌入的结构体:",
+		}), types.ExprString(e))
+
+		// 指针中的字段不会展开，但在Go中仍会与同名字段冲突
+		fields, err := p.collectEmbeddedFields(e.X)
+		for i := range fields {
+			fields[i].embedded = true
+		}
+		return fields, err
+	default:
+		return nil, nil
+	}
+
+	// 嵌入字段本身也参与名称冲突检查
+	fields := []structField{{field: &models.GoField{Name: name}, embedded: true}}
+
+	obj := p.lookupTypeName(expr)
+	if obj == nil || obj.Pkg() == nil || obj.IsAlias() || !p.isModulePkg(obj.Pkg()) {
+		return fields, nil
+	}
+	if _, ok := obj.Type().Underlying().(*types.Struct); !ok {
+		return fields, nil
+	}
+
+	pkg := p.findPackage(obj.Pkg().Path())
+	if pkg == nil {
+		return fields, nil
+	}
+	typeSpec, _ := findTypeSpec(pkg, obj.Name())
+	if typeSpec == nil || typeSpec.TypeParams != nil {
+		return fields, nil
+	}
+	structAst, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return fields, nil
+	}
+
+	// 在嵌入类型所在的包中解析其字段
+	curPkg := p.curPkg
+	p.curPkg = pkg
+	inner, err := p.collectStructFields(structAst.Fields)
+	p.curPkg = curPkg
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range inner {
+		f.field.Embeds = append([]string{name}, f.field.Embeds...)
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// promoteFields 按Go的字段提升规则选择字段
+// 同名字段中嵌入深度最浅的字段生效，同一深度存在多个同名字段时均不提升
+func promoteFields(fields []structField) []*models.GoField {
+	minDepth := make(map[string]int)
+	count := make(map[string]int)
+	for _, f := range fields {
+		depth := len(f.field.Embeds)
+		if cur, ok := minDepth[f.field.Name]; !ok || depth < cur {
+			minDepth[f.field.Name] = depth
+			count[f.field.Name] = 1
+		} else if depth == cur {
+			count[f.field.Name]++
+		}
+	}
+
+	result := make([]*models.GoField, 0, len(fields))
+	warned := make(map[string]bool)
+	for _, f := range fields {
+		name := f.field.Name
+		if f.embedded || len(f.field.Embeds) != minDepth[name] {
+			continue
+		}
+		if count[name] > 1 {
+			if !warned[name] {
+				warned[name] = true
+				log.Println(locales.MustLocalizeMessage(&i18n.Message{
+					ID:    "ffigen.srcparser.process.embed.conflict",
+					Other: " - 嵌入结构体中存在多个同名字段, 不会提升:",
+				}), name)
+			}
+			continue
+		}
+		result = append(result, f.field)
+	}
+	return result
+}
+
 // isErrorType 检查类型是否为错误类型
 // 如果Go类型是"error"则返回true
 func isErrorType(t models.GoType) bool {
@@ -486,6 +1906,8 @@ func isErrorType(t models.GoType) bool {
 // processFunctionReturnValues 确保函数返回值有正确的名称
 // 同时识别错误返回值并计算结果数量
 func processFunctionReturnValues(funcType *models.GoFuncType) {
+	funcType.HasParams = len(funcType.Params.Fields) > 0
+
 	fields := funcType.Results.Fields
 	if len(fields) == 0 {
 		return
@@ -521,9 +1943,140 @@ func processFunctionReturnValues(funcType *models.GoFuncType) {
 		funcType.Results.Fields = fields[:resultCount]
 	}
 
-	funcType.HasParams = len(funcType.Params.Fields) > 0
 	funcType.HasResults = len(funcType.Results.Fields) > 0
 	funcType.ResultCount = resultCount
 	funcType.IsAnonymousResults = isAnonymousResults
 	funcType.HasErr = hasErr
 }
+
+// processStreamResult 处理返回通道的流函数
+// 通道只能作为唯一的返回值并可附带error
+func processStreamResult(funcType *models.GoFuncType) error {
+	for _, field := range funcType.Params.Fields {
+		if _, ok := field.Type.(*models.GoChanType); ok {
+			return chanUnsupportedError(field.Type)
+		}
+	}
+
+	for _, field := range funcType.Results.Fields {
+		chanType, ok := field.Type.(*models.GoChanType)
+		if !ok {
+			continue
+		}
+
+		if funcType.ResultCount != 1 {
+			return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.stream.results",
+				Other: "函数 %s 返回通道时只能有一个通道返回值和可选的error",
+			}), funcType.Name)
+		}
+
+		// 通道的值通过Dart端口发送，结果中只保留错误
+		funcType.Stream = chanType
+		funcType.Results.Fields = nil
+		funcType.HasResults = false
+		funcType.ResultCount = 0
+		funcType.IsAnonymousResults = false
+	}
+	return nil
+}
+
+// processZeroCopy 处理标记了 //fgo:zerocopy 的函数
+// []byte 参数直接引用Dart传入的内存，[]byte 返回值的C内存直接交给Dart并在Dart对象被回收时释放
+func processZeroCopy(funcDecl *ast.FuncDecl, funcType *models.GoFuncType) error {
+	if !hasDirective(funcDecl.Doc, "zerocopy") {
+		return nil
+	}
+
+	if funcType.Stream != nil {
+		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.zerocopy.stream",
+			Other: "返回通道的函数 %s 不支持 //fgo:zerocopy",
+		}), funcType.Name)
+	}
+
+	found := false
+	for _, field := range funcType.Args() {
+		if field.Type != models.BasicTypeMap["[]byte"] {
+			continue
+		}
+		if funcType.HasContext {
+			// Dart端取消后Go函数可能仍在运行，参数内存无法在调用结束时释放，仍然复制
+			log.Println(locales.MustLocalizeMessage(&i18n.Message{
+				ID:    "ffigen.srcparser.process.zerocopy.context",
+				Other: " - 可取消的函数的 []byte 参数仍会复制:",
+			}), field.Name)
+			continue
+		}
+		field.Type = models.BytesViewType
+		found = true
+	}
+	for _, field := range funcType.Results.Fields {
+		if field.Type == models.BasicTypeMap["[]byte"] {
+			field.Type = models.ExternalBytesType
+			found = true
+		}
+	}
+
+	if !found {
+		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.zerocopy.unused",
+			Other: "函数 %s 标记了 //fgo:zerocopy, 但没有可以避免复制的 []byte 参数或返回值",
+		}), funcType.Name)
+	}
+	funcType.ZeroCopy = true
+	return nil
+}
+
+// processPackageConcurrency 处理包声明上的 //fgo:concurrency，设置所有异步调用默认的最大并发数
+// 多个文件都指定时数量必须相同
+func (p *GoSrcParser) processPackageConcurrency(file *ast.File) error {
+	limit, err := parseConcurrency(file.Doc, "package "+file.Name.Name)
+	if err != nil || limit == 0 {
+		return err
+	}
+
+	if p.concurrency != 0 && p.concurrency != limit {
+		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.concurrency.conflict",
+			Other: "包声明上的 //fgo:concurrency 数量不一致: %d 和 %d",
+		}), p.concurrency, limit)
+	}
+	p.concurrency = limit
+	return nil
+}
+
+// processConcurrency 处理标记了 //fgo:concurrency N 的函数
+// 函数的异步调用最多同时运行N个，超出的调用排队等待，同时仍受全局最大并发数的限制
+func processConcurrency(funcDecl *ast.FuncDecl, funcType *models.GoFuncType) error {
+	limit, err := parseConcurrency(funcDecl.Doc, funcType.Name)
+	if err != nil || limit == 0 {
+		return err
+	}
+
+	if funcType.Stream != nil {
+		return fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.concurrency.stream",
+			Other: "返回通道的函数 %s 不支持 //fgo:concurrency",
+		}), funcType.Name)
+	}
+	funcType.Concurrency = limit
+	return nil
+}
+
+// parseConcurrency 解析 //fgo:concurrency 指令的数量，没有指令时返回0
+func parseConcurrency(doc *ast.CommentGroup, target string) (int, error) {
+	args, ok := parseDirective(doc, "concurrency")
+	if !ok {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(args)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf(locales.MustLocalizeMessage(&i18n.Message{
+			ID:    "ffigen.srcparser.process.concurrency.invalid",
+			Other: "%s 的 //fgo:concurrency 需要一个正整数, 但得到 %q",
+		}), target, args)
+	}
+	return limit, nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type GoArrayType struct {
+	Inner GoType
+	Len   int
+}
+
+func (t *GoArrayType) String() string {
+	return "[" + strconv.Itoa(t.Len) + "]" + t.Inner.String()
+}
+
+// CType 返回元素的C类型，数组长度通过 CArraySuffix 附加在字段名之后
+func (t *GoArrayType) CType() string {
+	return t.Inner.CType()
+}
+
+func (t *GoArrayType) GoType() string {
+	return "[" + strconv.Itoa(t.Len) + "]" + t.Inner.GoType()
+}
+
+func (t *GoArrayType) GoCType() string {
+	return "[" + strconv.Itoa(t.Len) + "]" + t.Inner.GoCType()
+}
+
+func (t *GoArrayType) DartType() string {
+	if t.IsBytes() {
+		return "Uint8List"
+	}
+	return "List<" + t.Inner.DartType() + ">"
+}
+
+func (t *GoArrayType) DartCType() string {
+	return "ffi.Array<" + t.Inner.DartCType() + ">"
+}
+
+func (t *GoArrayType) DartDefault() string {
+	if t.IsBytes() {
+		return fmt.Sprintf("Uint8List(%d)", t.Len)
+	}
+	return fmt.Sprintf("List<%s>.generate(%d, (_) => %s, growable: false)", t.Inner.DartType(), t.Len, t.Inner.DartDefault())
+}
+
+func (t *GoArrayType) MapName() string {
+	return t.Inner.MapName() + "Array" + strconv.Itoa(t.Len)
+}
+
+func (t *GoArrayType) NeedMap() bool {
+	return true
+}
+
+// IsBytes 判断是否为字节数组，字节数组在Dart中使用 Uint8List 表示
+func (t *GoArrayType) IsBytes() bool {
+	goType := t.Inner.GoType()
+	return goType == "byte" || goType == "uint8"
+}
+
+// CArraySuffix 返回C结构体字段声明中的数组长度后缀
+func (t *GoArrayType) CArraySuffix() string {
+	return "[" + strconv.Itoa(t.Len) + "]"
+}
@@ -29,17 +29,30 @@ var BasicTypeMap = map[string]*GoBasicType{
 	"uint": {cType: "unsigned int", goType: "uint", goCType: "C.uint", dartCType: "ffi.UnsignedInt", dartType: "int", dartDefault: "0"},
 
 	"uintptr": {cType: "uintptr_t", goType: "uintptr", goCType: "C.uintptr_t", dartCType: "ffi.UintPtr", dartType: "int", dartDefault: "0"},
+
+	// 时间类型以微秒精度的 int64 传递，time.Time 为UTC时间戳
+	"time.Time":     {cType: "int64_t", goType: "time.Time", goCType: "C.int64_t", dartCType: "ffi.Int64", dartCValueType: "int", dartType: "DateTime", dartDefault: "DateTime.utc(1)", needMap: true, mapName: "Time"},
+	"time.Duration": {cType: "int64_t", goType: "time.Duration", goCType: "C.int64_t", dartCType: "ffi.Int64", dartCValueType: "int", dartType: "Duration", dartDefault: "Duration.zero", needMap: true, mapName: "Duration"},
 }
 
+// 标记 //fgo:zerocopy 的函数中的 []byte 参数和返回值，在Go和Dart之间传递时避免复制
+var (
+	// BytesViewType 参数直接引用Dart传入的内存，Go函数返回后不能继续持有
+	BytesViewType = &GoBasicType{cType: "FgData", goType: "[]byte", goCType: "C.FgData", dartCType: "_fgData", dartType: "Uint8List", dartDefault: "Uint8List(0)", needMap: true, mapName: "BytesView"}
+	// ExternalBytesType 返回值的C内存直接交给Dart，由 NativeFinalizer 释放
+	ExternalBytesType = &GoBasicType{cType: "FgData", goType: "[]byte", goCType: "C.FgData", dartCType: "_fgData", dartType: "Uint8List", dartDefault: "Uint8List(0)", needMap: true, mapName: "ExternalBytes"}
+)
+
 type GoBasicType struct {
 	cType string
 
 	goType  string
 	goCType string
 
-	dartType    string
-	dartCType   string
-	dartDefault string
+	dartType       string
+	dartCType      string
+	dartCValueType string
+	dartDefault    string
 
 	mapName string
 	needMap bool
@@ -76,3 +89,14 @@ func (t *GoBasicType) MapName() string {
 func (t *GoBasicType) NeedMap() bool {
 	return t.needMap
 }
+
+// DartCValueType 返回C结构体字段在Dart中的值类型，以 FgData 传递的类型返回空字符串
+func (t *GoBasicType) DartCValueType() string {
+	if t.cType == "FgData" {
+		return ""
+	}
+	if t.dartCValueType != "" {
+		return t.dartCValueType
+	}
+	return t.dartType
+}
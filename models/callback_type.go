@@ -0,0 +1,80 @@
+package models
+
+import "strings"
+
+// GoCallbackType 表示函数参数中的回调函数，如 onProgress func(done, total int64)
+// Dart闭包通过端口接收调用，Go端每次调用时将参数以结构体指针发送到端口
+// 回调只在Go函数返回前有效，函数返回后Go端发送0通知Dart关闭端口
+type GoCallbackType struct {
+	Name  string        // 函数名与参数名组合的名称
+	Scope string        // 方法回调所属的接收者，普通函数为空
+	Args  *GoStructType // 回调参数
+}
+
+func (t *GoCallbackType) String() string {
+	return t.GoType()
+}
+
+func (t *GoCallbackType) CType() string {
+	return "int64_t"
+}
+
+func (t *GoCallbackType) GoType() string {
+	builder := strings.Builder{}
+	builder.WriteString("func(")
+	for i, field := range t.Args.Fields {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(field.GoName())
+		builder.WriteString(" ")
+		builder.WriteString(field.GoType())
+	}
+	builder.WriteString(")")
+	return builder.String()
+}
+
+func (t *GoCallbackType) GoCType() string {
+	return "C.int64_t"
+}
+
+func (t *GoCallbackType) DartType() string {
+	builder := strings.Builder{}
+	builder.WriteString("void Function(")
+	for i, field := range t.Args.Fields {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(field.DartType())
+		builder.WriteString(" ")
+		builder.WriteString(field.DartName())
+	}
+	builder.WriteString(")?")
+	return builder.String()
+}
+
+func (t *GoCallbackType) DartCType() string {
+	return "ffi.Int64"
+}
+
+func (t *GoCallbackType) DartDefault() string {
+	return "null"
+}
+
+func (t *GoCallbackType) MapName() string {
+	return scopedCamel(t.Scope, t.Name, false) + "Callback"
+}
+
+func (t *GoCallbackType) NeedMap() bool {
+	return true
+}
+
+// DartCValueType 返回C结构体字段在Dart中的值类型，回调以Dart端口号传递
+func (t *GoCallbackType) DartCValueType() string {
+	return "int"
+}
+
+// HasArgs 判断回调是否有参数，无参数时Go端发送不含数据的指针
+func (t *GoCallbackType) HasArgs() bool {
+	return len(t.Args.Fields) > 0
+}
@@ -0,0 +1,65 @@
+package models
+
+// ChanDir 通道的方向
+type ChanDir int
+
+const (
+	ChanBoth ChanDir = iota // 双向通道 chan T
+	ChanRecv                // 只读通道 <-chan T
+	ChanSend                // 只写通道 chan<- T
+)
+
+// prefix 返回通道类型的Go语法前缀
+func (d ChanDir) prefix() string {
+	switch d {
+	case ChanRecv:
+		return "<-chan "
+	case ChanSend:
+		return "chan<- "
+	default:
+		return "chan "
+	}
+}
+
+// GoChanType 表示函数返回的可接收通道，在Dart中映射为 Stream
+// 通道中的每个值以指向C值的指针逐个发送到Dart端口
+type GoChanType struct {
+	Inner GoType
+	Dir   ChanDir
+}
+
+func (t *GoChanType) String() string {
+	return t.Dir.prefix() + t.Inner.String()
+}
+
+func (t *GoChanType) CType() string {
+	return t.Inner.CType() + "*"
+}
+
+func (t *GoChanType) GoType() string {
+	return t.Dir.prefix() + t.Inner.GoType()
+}
+
+func (t *GoChanType) GoCType() string {
+	return "*" + t.Inner.GoCType()
+}
+
+func (t *GoChanType) DartType() string {
+	return "Stream<" + t.Inner.DartType() + ">"
+}
+
+func (t *GoChanType) DartCType() string {
+	return "ffi.Pointer<" + t.Inner.DartCType() + ">"
+}
+
+func (t *GoChanType) DartDefault() string {
+	return "const Stream.empty()"
+}
+
+func (t *GoChanType) MapName() string {
+	return t.Inner.MapName() + "Chan"
+}
+
+func (t *GoChanType) NeedMap() bool {
+	return true
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/iancoleman/strcase"
+)
+
+// GoEnumType 表示由命名基础类型和同类型常量组成的枚举，如 type Color int
+type GoEnumType struct {
+	Name   string
+	Pkg    string // 导入类型所在的包名，当前包中的类型为空
+	Base   *GoBasicType
+	Values []*GoEnumValue
+}
+
+// GoEnumValue 表示枚举的一个常量值
+type GoEnumValue struct {
+	Name  string
+	Value string // 常量值的字面量表示，整数为十进制，字符串为未转义的原始内容
+	enum  *GoEnumType
+}
+
+func (t *GoEnumType) String() string {
+	return t.Name
+}
+
+func (t *GoEnumType) CType() string {
+	return t.Base.CType()
+}
+
+func (t *GoEnumType) GoType() string {
+	if t.Pkg != "" {
+		return t.Pkg + "." + t.Name
+	}
+	return t.Name
+}
+
+func (t *GoEnumType) GoCType() string {
+	return t.Base.GoCType()
+}
+
+func (t *GoEnumType) DartType() string {
+	return strcase.ToCamel(t.Name)
+}
+
+func (t *GoEnumType) DartCType() string {
+	return t.Base.DartCType()
+}
+
+// DartDefault 返回零值对应的枚举成员，不存在零值时使用第一个成员
+func (t *GoEnumType) DartDefault() string {
+	zero := "0"
+	if t.IsString() {
+		zero = ""
+	}
+	for _, v := range t.Values {
+		if v.Value == zero {
+			return t.DartType() + "." + v.DartName()
+		}
+	}
+	return t.DartType() + "." + t.Values[0].DartName()
+}
+
+func (t *GoEnumType) MapName() string {
+	return strcase.ToCamel(t.Name)
+}
+
+func (t *GoEnumType) NeedMap() bool {
+	return true
+}
+
+// DartCValueType 返回C结构体字段在Dart中的值类型，字符串枚举返回空字符串
+func (t *GoEnumType) DartCValueType() string {
+	return t.Base.DartCValueType()
+}
+
+// IsString 判断枚举的基础类型是否为字符串
+func (t *GoEnumType) IsString() bool {
+	return t.Base.GoType() == "string"
+}
+
+// AddValue 添加一个枚举常量值
+func (t *GoEnumType) AddValue(name, value string) {
+	t.Values = append(t.Values, &GoEnumValue{Name: name, Value: value, enum: t})
+}
+
+// dartReservedNames Dart枚举中不能作为成员名称的标识符
+var dartReservedNames = []string{
+	"assert", "break", "case", "catch", "class", "const", "continue", "default", "do", "else",
+	"enum", "extends", "false", "final", "finally", "for", "if", "in", "is", "new", "null",
+	"rethrow", "return", "super", "switch", "this", "throw", "true", "try", "var", "void",
+	"while", "with", "values", "value", "index", "hashCode", "name", "runtimeType",
+}
+
+// DartName 返回Dart枚举成员名称，去除与类型名相同的前缀并转换为小驼峰
+func (v *GoEnumValue) DartName() string {
+	name := v.dartName()
+
+	// 去除前缀后与其他成员重名时使用完整名称
+	for _, other := range v.enum.Values {
+		if other != v && other.dartName() == name {
+			name = strcase.ToLowerCamel(v.Name)
+			break
+		}
+	}
+
+	if slices.Contains(dartReservedNames, name) {
+		name += "Value"
+	}
+	return name
+}
+
+func (v *GoEnumValue) dartName() string {
+	name := v.Name
+	if rest, ok := strings.CutPrefix(name, v.enum.Name); ok {
+		// 去除前缀后为空或以数字开头时保留完整名称
+		rest = strings.TrimLeft(rest, "_")
+		if rest != "" && !unicode.IsDigit(rune(rest[0])) {
+			name = rest
+		}
+	}
+	return strcase.ToLowerCamel(name)
+}
+
+// DartValue 返回枚举值在Dart中的字面量
+func (v *GoEnumValue) DartValue() string {
+	if !v.enum.IsString() {
+		return v.Value
+	}
+	// Dart字符串中的 $ 表示插值，需要转义
+	return strings.ReplaceAll(strconv.Quote(v.Value), "$", `\$`)
+}
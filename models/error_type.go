@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/iancoleman/strcase"
+)
+
+// GoErrorType 表示实现了error接口的导出类型，在Dart中生成继承 FgFfiException 的异常类
+// 错误以JSON格式跨FFI传递，只有基础类型的导出字段会传递到Dart
+type GoErrorType struct {
+	Name      string
+	Pkg       string     // 导入类型所在的包名，当前包中的类型为空
+	PtrRecv   bool       // Error 方法是否为指针接收者
+	Fields    []*GoField // 传递到Dart的导出字段
+	CodeField *GoField   // Dart名称为 code 的整数字段，错误链中没有实现 Code() int 的错误时作为错误码
+}
+
+func (t *GoErrorType) String() string {
+	return t.Name
+}
+
+// GoType 返回实现error接口的Go类型，用于在错误链中匹配
+func (t *GoErrorType) GoType() string {
+	if t.PtrRecv {
+		return "*" + t.BaseGoType()
+	}
+	return t.BaseGoType()
+}
+
+// BaseGoType 返回不含指针的Go类型，值接收者的错误类型以值和指针形式都可以匹配
+func (t *GoErrorType) BaseGoType() string {
+	if t.Pkg != "" {
+		return t.Pkg + "." + t.Name
+	}
+	return t.Name
+}
+
+// EncodeAsString 判断字段是否以字符串传递，JSON中超出 int64 范围的无符号整数在Dart中无法解码为 int
+func (t *GoErrorType) EncodeAsString(field *GoField) bool {
+	switch field.GoType() {
+	case "uint64", "uint", "uintptr":
+		return true
+	}
+	return false
+}
+
+func (t *GoErrorType) DartType() string {
+	return strcase.ToCamel(t.Name)
+}
+
+// DartDecode 返回从JSON字段表中读取字段值的Dart表达式
+func (t *GoErrorType) DartDecode(field *GoField) string {
+	key := fmt.Sprintf("fields['%s']", field.GoName())
+	if t.EncodeAsString(field) {
+		// 与FFI结构体中的无符号整数一致，超出 int 范围的值按64位补码转换
+		return fmt.Sprintf("BigInt.parse(%s as String? ?? '0').toSigned(64).toInt()", key)
+	}
+	switch field.DartType() {
+	case "double":
+		// JSON中的整数值会被解码为int
+		return fmt.Sprintf("(%s as num?)?.toDouble() ?? 0.0", key)
+	default:
+		return fmt.Sprintf("%s as %s? ?? %s", key, field.DartType(), field.DartDefault())
+	}
+}
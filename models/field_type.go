@@ -1,10 +1,18 @@
 package models
 
-import "github.com/iancoleman/strcase"
+import (
+	"regexp"
+	"slices"
+
+	"github.com/iancoleman/strcase"
+)
 
 type GoField struct {
-	Name string
-	Type GoType
+	Name     string
+	Type     GoType
+	Embeds   []string // 提升字段所在的嵌入结构体字段名，由外到内排列，直接字段为空
+	Rename   string   // 通过结构体标签指定的Dart字段名
+	Readonly bool     // 是否在Dart中生成为final字段
 }
 
 func (f *GoField) InnerMost() GoType {
@@ -27,11 +35,16 @@ func (f *GoField) CName() string {
 	return strcase.ToSnake(f.Name)
 }
 
+// GoName 返回Go中的字段名，提升字段按Go的提升规则可以直接访问
 func (f *GoField) GoName() string {
 	return f.Name
 }
 
+// DartName 返回Dart中的字段名，优先使用结构体标签中指定的名称
 func (f *GoField) DartName() string {
+	if f.Rename != "" {
+		return f.Rename
+	}
 	return strcase.ToLowerCamel(f.Name)
 }
 
@@ -70,3 +83,52 @@ func (f *GoField) MapName() string {
 func (f *GoField) NeedMap() bool {
 	return f.Type.NeedMap()
 }
+
+// CArraySuffix 返回C结构体字段声明中的数组长度后缀，非数组类型返回空字符串
+func (f *GoField) CArraySuffix() string {
+	if a, ok := f.Type.(*GoArrayType); ok {
+		return a.CArraySuffix()
+	}
+	return ""
+}
+
+// ArrayLen 返回固定大小数组的长度，非数组类型返回0
+func (f *GoField) ArrayLen() int {
+	if a, ok := f.Type.(*GoArrayType); ok {
+		return a.Len
+	}
+	return 0
+}
+
+// DartCValueType 返回Dart C结构体中以原生类型存储的字段对应的Dart类型，其他类型返回空字符串
+func (f *GoField) DartCValueType() string {
+	switch t := f.Type.(type) {
+	case *GoBasicType:
+		return t.DartCValueType()
+	case *GoEnumType:
+		return t.DartCValueType()
+	case *GoHandleType:
+		return t.DartCValueType()
+	case *GoCallbackType:
+		return t.DartCValueType()
+	case *GoNamedType:
+		return t.DartCValueType()
+	}
+	return ""
+}
+
+// dartIdentifierRegexp Dart标识符的格式
+var dartIdentifierRegexp = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// dartFieldReservedNames Dart类中不能作为字段名称的标识符
+var dartFieldReservedNames = []string{
+	"assert", "break", "case", "catch", "class", "const", "continue", "default", "do", "else",
+	"enum", "extends", "false", "final", "finally", "for", "if", "in", "is", "new", "null",
+	"rethrow", "return", "super", "switch", "this", "throw", "true", "try", "var", "void",
+	"while", "with", "hashCode", "runtimeType", "toString", "noSuchMethod",
+}
+
+// IsDartFieldName 判断名称是否可以作为Dart类的字段名
+func IsDartFieldName(name string) bool {
+	return dartIdentifierRegexp.MatchString(name) && !slices.Contains(dartFieldReservedNames, name)
+}
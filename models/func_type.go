@@ -15,14 +15,82 @@ type GoFuncType struct {
 	ResultCount        int           //返回数量
 	IsAnonymousResults bool          //是否匿名的返回
 	HasErr             bool          //是否存在错误字段
+	Method             string        //方法名，普通函数为空
+	Recv               GoType        //方法接收者的结构体或句柄类型，普通函数为nil
+	PtrRecv            bool          //是否为需要同步字段的结构体指针接收者
+	HasContext         bool          //第一个参数是否为 context.Context
+	Stream             *GoChanType   //返回的通道，非流函数为nil
+	ZeroCopy           bool          //是否标记了 //fgo:zerocopy，[]byte 参数和返回值不复制
+	Concurrency        int           //标记的 //fgo:concurrency 数量，异步调用最多同时运行的数量，0表示只受全局限制
+}
+
+// BytesViews 返回直接引用Dart内存的 []byte 参数，调用结束后才能释放
+func (t *GoFuncType) BytesViews() []*GoField {
+	var fields []*GoField
+	for _, field := range t.Params.Fields {
+		if field.Type == BytesViewType {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Cancelable 判断是否需要导出取消函数，接收 context.Context 的函数和流函数可以从Dart取消
+func (t *GoFuncType) Cancelable() bool {
+	return t.HasContext || t.Stream != nil
+}
+
+// RecvFieldCount 方法参数中接收者占用的字段数量，指针接收者额外传递对象句柄
+func (t *GoFuncType) RecvFieldCount() int {
+	if t.Recv == nil {
+		return 0
+	}
+	if t.PtrRecv {
+		return 2
+	}
+	return 1
+}
+
+// Args 返回调用Go函数或方法时传递的参数，不包含接收者
+func (t *GoFuncType) Args() []*GoField {
+	return t.Params.Fields[t.RecvFieldCount():]
+}
+
+// Callbacks 返回回调类型的参数
+func (t *GoFuncType) Callbacks() []*GoField {
+	var fields []*GoField
+	for _, field := range t.Params.Fields {
+		if _, ok := field.Type.(*GoCallbackType); ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// DartMethodName 返回Dart类中实例方法的名称
+func (t *GoFuncType) DartMethodName() string {
+	return strcase.ToLowerCamel(t.Method)
 }
 
 func (t *GoFuncType) String() string {
 	return t.Name
 }
 
+// nameParts 返回生成名称使用的接收者和名称，普通函数的接收者为空
+func (t *GoFuncType) nameParts() (string, string) {
+	if t.Method == "" {
+		return "", t.Name
+	}
+	return t.Recv.String(), t.Method
+}
+
+// CType 返回导出的C函数名，方法的接收者和方法名之间用双下划线分隔，避免与同名函数冲突
 func (t *GoFuncType) CType() string {
-	return "fg_" + strcase.ToSnake(t.Name)
+	scope, name := t.nameParts()
+	if scope == "" {
+		return "fg_" + strcase.ToSnake(name)
+	}
+	return "fg_" + strcase.ToSnake(scope) + "__" + strcase.ToSnake(name)
 }
 
 func (t *GoFuncType) GoType() string {
@@ -34,11 +102,13 @@ func (t *GoFuncType) GoCType() string {
 }
 
 func (t *GoFuncType) DartType() string {
-	return strcase.ToLowerCamel(t.Name)
+	scope, name := t.nameParts()
+	return scopedCamel(scope, name, true)
 }
 
 func (t *GoFuncType) DartCType() string {
-	return "_fg" + strcase.ToCamel(t.Name)
+	scope, name := t.nameParts()
+	return "_fg" + scopedCamel(scope, name, false)
 }
 
 func (t *GoFuncType) DartDefault() string {
@@ -0,0 +1,59 @@
+package models
+
+import "github.com/iancoleman/strcase"
+
+// GoHandleType 表示以句柄形式传递的Go对象指针，Go对象保存在句柄表中，Dart只持有整数句柄
+type GoHandleType struct {
+	Name    string
+	Pkg     string        // 导入类型所在的包名，当前包中的类型为空
+	Methods []*GoFuncType // 导出为Dart实例方法的方法
+}
+
+func (t *GoHandleType) String() string {
+	return t.Name
+}
+
+func (t *GoHandleType) CType() string {
+	return "uintptr_t"
+}
+
+func (t *GoHandleType) GoType() string {
+	if t.Pkg != "" {
+		return "*" + t.Pkg + "." + t.Name
+	}
+	return "*" + t.Name
+}
+
+func (t *GoHandleType) GoCType() string {
+	return "C.uintptr_t"
+}
+
+// DartType 返回可空的Dart包装类，Go中的nil指针对应null
+func (t *GoHandleType) DartType() string {
+	return t.DartClassName() + "?"
+}
+
+func (t *GoHandleType) DartCType() string {
+	return "ffi.UintPtr"
+}
+
+func (t *GoHandleType) DartDefault() string {
+	return "null"
+}
+
+func (t *GoHandleType) MapName() string {
+	return strcase.ToCamel(t.Name) + "Handle"
+}
+
+func (t *GoHandleType) NeedMap() bool {
+	return true
+}
+
+func (t *GoHandleType) DartCValueType() string {
+	return "int"
+}
+
+// DartClassName 返回Dart包装类的名称
+func (t *GoHandleType) DartClassName() string {
+	return strcase.ToCamel(t.Name)
+}
@@ -7,7 +7,9 @@ import (
 )
 
 type GoIdentType struct {
-	Name string
+	Name  string
+	Pkg   string // 导入类型所在的包名，当前包中的类型为空
+	Scope string // 方法参数和返回值结构体所属的接收者，普通类型为空
 }
 
 func (t *GoIdentType) String() string {
@@ -15,26 +17,37 @@ func (t *GoIdentType) String() string {
 }
 
 func (t *GoIdentType) CType() string {
-	return "struct Fg" + strcase.ToCamel(t.Name)
+	return "struct " + t.CName()
+}
+
+// CName 返回C结构体的类型名
+func (t *GoIdentType) CName() string {
+	return "Fg" + scopedCamel(t.Scope, t.Name, false)
 }
 
 func (t *GoIdentType) GoType() string {
+	if t.Pkg != "" {
+		return t.Pkg + "." + t.Name
+	}
+	if t.Scope != "" {
+		return scopedCamel(t.Scope, t.Name, true)
+	}
 	return t.Name
 }
 
 func (t *GoIdentType) GoCType() string {
-	return "C.Fg" + strcase.ToCamel(t.Name)
+	return "C." + t.CName()
 }
 
 func (t *GoIdentType) DartType() string {
 	if unicode.IsLower(rune(t.Name[0])) {
-		return "_" + strcase.ToLowerCamel(t.Name)
+		return "_" + scopedCamel(t.Scope, t.Name, true)
 	}
-	return strcase.ToCamel(t.Name)
+	return scopedCamel(t.Scope, t.Name, false)
 }
 
 func (t *GoIdentType) DartCType() string {
-	return "_fg" + strcase.ToCamel(t.Name)
+	return "_fg" + scopedCamel(t.Scope, t.Name, false)
 }
 
 func (t *GoIdentType) DartDefault() string {
@@ -42,9 +55,22 @@ func (t *GoIdentType) DartDefault() string {
 }
 
 func (t *GoIdentType) MapName() string {
-	return strcase.ToCamel(t.Name)
+	return scopedCamel(t.Scope, t.Name, false)
 }
 
 func (t *GoIdentType) NeedMap() bool {
 	return true
 }
+
+// scopedCamel 返回驼峰形式的名称，方法的接收者和名称分别转换后用下划线连接
+// 避免方法生成的名称与接收者和方法名拼接后同名的函数冲突
+func scopedCamel(scope, name string, lowerFirst bool) string {
+	convert := strcase.ToCamel
+	if lowerFirst {
+		convert = strcase.ToLowerCamel
+	}
+	if scope == "" {
+		return convert(name)
+	}
+	return convert(scope) + "_" + strcase.ToCamel(name)
+}
@@ -0,0 +1,52 @@
+package models
+
+type GoMapType struct {
+	Key   GoType
+	Value GoType
+}
+
+func (t *GoMapType) String() string {
+	return "map[" + t.Key.String() + "]" + t.Value.String()
+}
+
+func (t *GoMapType) CType() string {
+	return "FgData"
+}
+
+func (t *GoMapType) GoType() string {
+	return "map[" + t.Key.GoType() + "]" + t.Value.GoType()
+}
+
+func (t *GoMapType) GoCType() string {
+	return "C.FgData"
+}
+
+func (t *GoMapType) DartType() string {
+	return "Map<" + t.Key.DartType() + ", " + t.Value.DartType() + ">"
+}
+
+func (t *GoMapType) DartCType() string {
+	return "_fgData"
+}
+
+func (t *GoMapType) DartDefault() string {
+	return "{}"
+}
+
+func (t *GoMapType) MapName() string {
+	return t.Key.MapName() + t.Value.MapName() + "Map"
+}
+
+func (t *GoMapType) NeedMap() bool {
+	return true
+}
+
+// Keys 返回用于传递键的切片类型
+func (t *GoMapType) Keys() *GoSliceType {
+	return &GoSliceType{Inner: t.Key}
+}
+
+// Values 返回用于传递值的切片类型
+func (t *GoMapType) Values() *GoSliceType {
+	return &GoSliceType{Inner: t.Value}
+}
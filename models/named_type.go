@@ -0,0 +1,70 @@
+package models
+
+import "github.com/iancoleman/strcase"
+
+// GoNamedType 表示底层为可桥接类型的命名类型，如 type UserID int64、type Tags []string
+// Go代码中保留类型名并在映射时转换，Dart中默认使用底层类型，标记 //fgo:extension 时生成 extension type
+type GoNamedType struct {
+	Name       string
+	Pkg        string // 导入类型所在的包名，当前包中的类型为空
+	Underlying GoType
+	Extension  bool // 是否在Dart中生成 extension type
+}
+
+func (t *GoNamedType) String() string {
+	return t.Name
+}
+
+func (t *GoNamedType) CType() string {
+	return t.Underlying.CType()
+}
+
+func (t *GoNamedType) GoType() string {
+	if t.Pkg != "" {
+		return t.Pkg + "." + t.Name
+	}
+	return t.Name
+}
+
+func (t *GoNamedType) GoCType() string {
+	return t.Underlying.GoCType()
+}
+
+func (t *GoNamedType) DartType() string {
+	if t.Extension {
+		return strcase.ToCamel(t.Name)
+	}
+	return t.Underlying.DartType()
+}
+
+func (t *GoNamedType) DartCType() string {
+	return t.Underlying.DartCType()
+}
+
+func (t *GoNamedType) DartDefault() string {
+	if t.Extension {
+		return t.DartType() + "(" + t.Underlying.DartDefault() + ")"
+	}
+	return t.Underlying.DartDefault()
+}
+
+func (t *GoNamedType) MapName() string {
+	return strcase.ToCamel(t.Name)
+}
+
+func (t *GoNamedType) NeedMap() bool {
+	return true
+}
+
+// DartCValueType 返回C结构体字段在Dart中的值类型，与底层类型一致
+func (t *GoNamedType) DartCValueType() string {
+	switch u := t.Underlying.(type) {
+	case *GoBasicType:
+		return u.DartCValueType()
+	case *GoEnumType:
+		return u.DartCValueType()
+	case *GoNamedType:
+		return u.DartCValueType()
+	}
+	return ""
+}
@@ -4,6 +4,13 @@ type Package struct {
 	ProjectNaming
 	Module  string
 	PkgPath string
+	Imports map[string]string // 生成代码需要导入的包，键为包路径，值为包名
 	Structs []*GoStructType
+	Enums   []*GoEnumType
+	Named   []*GoNamedType
+	Handles []*GoHandleType
+	Errors  []*GoErrorType
 	Funcs   []*GoFuncType
+
+	Concurrency int // 包声明上 //fgo:concurrency 指定的异步调用默认最大并发数，0表示不限制
 }
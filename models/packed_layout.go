@@ -0,0 +1,78 @@
+package models
+
+// packedFieldTypes 可以紧凑布局的字段类型及其在Dart ByteData中的读写类型
+var packedFieldTypes = map[string]struct {
+	size     int
+	byteData string
+}{
+	"bool":    {1, "Uint8"},
+	"int8":    {1, "Int8"},
+	"byte":    {1, "Uint8"},
+	"uint8":   {1, "Uint8"},
+	"int16":   {2, "Int16"},
+	"uint16":  {2, "Uint16"},
+	"int32":   {4, "Int32"},
+	"uint32":  {4, "Uint32"},
+	"float32": {4, "Float32"},
+	"int64":   {8, "Int64"},
+	"uint64":  {8, "Uint64"},
+	"float64": {8, "Float64"},
+}
+
+// PackedLayout 只包含固定大小数值字段的结构体在切片中的紧凑布局
+// 字段按自然对齐排列，Go和Dart按偏移量直接读写整块内存，不需要逐个元素转换
+type PackedLayout struct {
+	Struct *GoStructType
+	Fields []*PackedField
+	Size   int // 每个元素占用的字节数
+}
+
+// PackedField 紧凑布局中的字段
+type PackedField struct {
+	*GoField
+	Offset   int    // 字段在元素中的偏移量
+	Size     int    // 字段占用的字节数
+	ByteData string // Dart ByteData 读写字段使用的类型，如 Int32
+}
+
+// IsBool 判断字段是否为布尔类型，布尔值在Dart中以 Uint8 读写
+func (f *PackedField) IsBool() bool {
+	return f.Type.GoType() == "bool"
+}
+
+// NewPackedLayout 计算结构体的紧凑布局，存在不是固定大小数值的字段时返回nil
+func NewPackedLayout(structType *GoStructType) *PackedLayout {
+	if len(structType.Fields) == 0 {
+		return nil
+	}
+
+	layout := &PackedLayout{Struct: structType}
+	align := 1
+	for _, field := range structType.Fields {
+		basicType, ok := field.Type.(*GoBasicType)
+		if !ok {
+			return nil
+		}
+		fieldType, ok := packedFieldTypes[basicType.GoType()]
+		if !ok {
+			return nil
+		}
+
+		layout.Size = alignTo(layout.Size, fieldType.size)
+		layout.Fields = append(layout.Fields, &PackedField{
+			GoField:  field,
+			Offset:   layout.Size,
+			Size:     fieldType.size,
+			ByteData: fieldType.byteData,
+		})
+		layout.Size += fieldType.size
+		align = max(align, fieldType.size)
+	}
+	layout.Size = alignTo(layout.Size, align)
+	return layout
+}
+
+// alignTo 将偏移量向上对齐到 align 的整数倍
+func alignTo(offset, align int) int {
+	return (offset + align - 1) / align * align
+}
@@ -35,18 +35,25 @@ func NewProjectNaming(projectName string) ProjectNaming {
 	}
 }
 
-// CreateTimestampFile 创建 .timestamp 文件
-func (p *ProjectNaming) CreateTimestampFile(destDir string) error {
-	timestampFile := filepath.Join(destDir, ".timestamp")
-	// 读取文件内容
-	content, _ := os.ReadFile(timestampFile)
+// LoadTimestampFile 读取 .timestamp 文件中的时间戳，文件不存在或内容无效时返回false
+func (p *ProjectNaming) LoadTimestampFile(destDir string) bool {
+	content, _ := os.ReadFile(filepath.Join(destDir, ".timestamp"))
 	if len(content) > 0 {
 		timestamp, _ := strconv.ParseInt(string(content), 10, 64)
 		if timestamp > 0 {
 			p.Timestamp = timestamp
-			return nil
+			return true
 		}
 	}
+	return false
+}
+
+// CreateTimestampFile 创建 .timestamp 文件
+func (p *ProjectNaming) CreateTimestampFile(destDir string) error {
+	timestampFile := filepath.Join(destDir, ".timestamp")
+	if p.LoadTimestampFile(destDir) {
+		return nil
+	}
 	// 创建文件
 	if err := os.WriteFile(timestampFile, fmt.Append(nil, p.Timestamp), 0644); err != nil {
 		return err
@@ -1,5 +1,20 @@
 package models
 
+// typedDataTypes 数值切片在Dart中对应的 TypedData 类型，元素在Go、C和Dart中的大小一致，可以整块复制
+var typedDataTypes = map[string]string{
+	"int8":    "Int8List",
+	"byte":    "Uint8List",
+	"uint8":   "Uint8List",
+	"int16":   "Int16List",
+	"uint16":  "Uint16List",
+	"int32":   "Int32List",
+	"uint32":  "Uint32List",
+	"int64":   "Int64List",
+	"uint64":  "Uint64List",
+	"float32": "Float32List",
+	"float64": "Float64List",
+}
+
 type GoSliceType struct {
 	Inner GoType
 }
@@ -21,6 +36,9 @@ func (t *GoSliceType) GoCType() string {
 }
 
 func (t *GoSliceType) DartType() string {
+	if typedData := t.TypedData(); typedData != "" {
+		return typedData
+	}
 	return "List<" + t.Inner.DartType() + ">"
 }
 
@@ -29,6 +47,9 @@ func (t *GoSliceType) DartCType() string {
 }
 
 func (t *GoSliceType) DartDefault() string {
+	if typedData := t.TypedData(); typedData != "" {
+		return typedData + "(0)"
+	}
 	return "[]"
 }
 
@@ -39,3 +60,11 @@ func (t *GoSliceType) MapName() string {
 func (t *GoSliceType) NeedMap() bool {
 	return true
 }
+
+// TypedData 返回数值切片在Dart中对应的 TypedData 类型，如 Int32List，其他切片返回空字符串
+func (t *GoSliceType) TypedData() string {
+	if basicType, ok := t.Inner.(*GoBasicType); ok {
+		return typedDataTypes[basicType.GoType()]
+	}
+	return ""
+}
@@ -3,8 +3,9 @@ package models
 import "github.com/iancoleman/strcase"
 
 type GoStructType struct {
-	Type   GoType
-	Fields []*GoField
+	Type    GoType
+	Fields  []*GoField
+	Methods []*GoFuncType // 导出为Dart实例方法的结构体方法
 }
 
 func (t *GoStructType) String() string {
@@ -12,7 +13,10 @@ func (t *GoStructType) String() string {
 }
 
 func (t *GoStructType) CType() string {
-	return "Fg" + strcase.ToCamel(t.Type.GoType())
+	if identType, ok := t.Type.(*GoIdentType); ok {
+		return identType.CName()
+	}
+	return "Fg" + strcase.ToCamel(t.Type.String())
 }
 
 func (t *GoStructType) GoType() string {
@@ -42,3 +46,38 @@ func (t *GoStructType) MapName() string {
 func (t *GoStructType) NeedMap() bool {
 	return true
 }
+
+// MapFromFields 返回Dart对象转换为C结构体时字段的转换顺序，回调字段排在最后
+// 回调转换时会打开接收调用的端口，其他字段转换失败时Go函数不会被调用，端口也就不会被关闭
+func (t *GoStructType) MapFromFields() []*GoField {
+	fields := make([]*GoField, 0, len(t.Fields))
+	var callbacks []*GoField
+	for _, field := range t.Fields {
+		if _, ok := field.Type.(*GoCallbackType); ok {
+			callbacks = append(callbacks, field)
+		} else {
+			fields = append(fields, field)
+		}
+	}
+	return append(fields, callbacks...)
+}
+
+// HasReadonly 判断是否存在只读字段
+func (t *GoStructType) HasReadonly() bool {
+	for _, field := range t.Fields {
+		if field.Readonly {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPtrMethods 判断是否存在指针接收者的方法，存在时Dart对象需要持有Go对象句柄
+func (t *GoStructType) HasPtrMethods() bool {
+	for _, method := range t.Methods {
+		if method.PtrRecv {
+			return true
+		}
+	}
+	return false
+}
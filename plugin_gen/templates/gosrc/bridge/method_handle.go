@@ -4,6 +4,8 @@ package bridge
 import (
 	"errors"
 	"fmt"
+	"runtime/debug"
+	"sync/atomic"
 )
 
 type MethodHandle func(method int, data []byte) ([]byte, error)
@@ -16,7 +18,7 @@ func callGoMethod(method int, data []byte) (result []byte, err error) {
 	}
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("panic err: %v", r)
+			err = panicError(r)
 		}
 	}()
 
@@ -26,3 +28,30 @@ func callGoMethod(method int, data []byte) (result []byte, err error) {
 	}
 	return
 }
+
+// PanicHook 在桥接方法发生panic时调用，可用于将panic上报到崩溃收集服务
+type PanicHook func(value any, stack []byte)
+
+var panicHook atomic.Pointer[PanicHook]
+
+// panicStackSeparator 分隔panic错误信息和调用栈，Dart据此抛出 FgBridgePanicException
+const panicStackSeparator = "\n--- go stack ---\n"
+
+// panicError 将recover得到的值转换为附带调用栈的错误，并调用已设置的PanicHook
+func panicError(r any) error {
+	stack := debug.Stack()
+	callPanicHook(r, stack)
+	return fmt.Errorf("panic err: %v%s%s", r, panicStackSeparator, stack)
+}
+
+func callPanicHook(r any, stack []byte) {
+	hook := panicHook.Load()
+	if hook == nil {
+		return
+	}
+	// 回调自身的panic不能影响错误的返回
+	defer func() {
+		_ = recover()
+	}()
+	(*hook)(r, stack)
+}
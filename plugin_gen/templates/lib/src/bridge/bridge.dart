@@ -19,6 +19,30 @@ class FgBridgeException implements Exception {
   String toString() => 'FgBridgeException: $message';
 }
 
+/// Go桥接方法发生panic时抛出的异常，[stack] 为发生panic的goroutine调用栈
+class FgBridgePanicException extends FgBridgeException {
+  final String stack;
+
+  const FgBridgePanicException(super.message, {required this.stack});
+
+  @override
+  String toString() => 'FgBridgePanicException: $message\n$stack';
+}
+
+// 与Go端 panicStackSeparator 一致
+const _panicStackSeparator = '\n--- go stack ---\n';
+
+FgBridgeException _bridgeException(String error) {
+  final index = error.indexOf(_panicStackSeparator);
+  if (!error.startsWith('panic err: ') || index < 0) {
+    return FgBridgeException(error);
+  }
+  return FgBridgePanicException(
+    error.substring(0, index),
+    stack: error.substring(index + _panicStackSeparator.length),
+  );
+}
+
 class FgBridge {
   static final _api = _bridge();
 
@@ -81,7 +105,7 @@ class _bridge {
     final response = _fgCallGoMethod(request);
     final (result, error) = _mapFromFgResponse(response);
     if (error != null) {
-      throw FgBridgeException(error);
+      throw _bridgeException(error);
     }
     return result;
   }
@@ -96,7 +120,7 @@ class _bridge {
     final (result, error) = _mapFromFgResponse(responsePtr[0]);
     malloc.free(responsePtr);
     if (error != null) {
-      throw FgBridgeException(error);
+      throw _bridgeException(error);
     }
     return result;
   }
@@ -106,7 +130,7 @@ class _bridge {
     final response = _fgCallPlatformMethod(request);
     final (result, error) = _mapFromFgResponse(response);
     if (error != null) {
-      throw FgBridgeException(error);
+      throw _bridgeException(error);
     }
     return result;
   }
@@ -121,7 +145,7 @@ class _bridge {
     final (result, error) = _mapFromFgResponse(responsePtr[0]);
     malloc.free(responsePtr);
     if (error != null) {
-      throw FgBridgeException(error);
+      throw _bridgeException(error);
     }
     return result;
   }